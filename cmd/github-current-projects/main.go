@@ -1,9 +1,18 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/template"
 	"time"
 
 	"github.com/shinshin86/github-current-projects/internal/cli"
@@ -11,111 +20,438 @@ import (
 	"github.com/shinshin86/github-current-projects/internal/githubapi"
 )
 
+// Exit codes. API failures get distinct codes so callers can tell a typo
+// from a transient outage without parsing stderr.
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitNotFound    = 3
+	exitAuth        = 4
+	exitRateLimit   = 5
+	exitServerError = 6
+	exitStale       = 7
+)
+
 func main() {
-	os.Exit(run(os.Args[1:]))
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
 }
 
-func run(args []string) int {
-	logger := log.New(os.Stderr, "[github-current-projects] ", log.LstdFlags)
+// run executes the command with args, writing output to stdout and logs and
+// errors to stderr, and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	logger := log.New(stderr, "[github-current-projects] ", log.LstdFlags)
 
-	opts, err := cli.ParseArgs(args, os.Stderr)
+	opts, err := cli.ParseArgs(args, stderr)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(stderr, "Error: %v\n", err)
 		if cli.IsUsageError(err) {
-			return 2
+			return exitUsage
 		}
-		return 1
+		return exitError
 	}
 
 	// Resolve token: CLI flag takes priority, then GITHUB_TOKEN env var
-	token := opts.Token
-	if token == "" {
-		token = os.Getenv("GITHUB_TOKEN")
-	}
+	token := cli.ResolveToken(opts.Token)
 
 	if err := cli.ValidateOptions(opts, token); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(stderr, "Error: %v\n", err)
 		if cli.IsUsageError(err) {
-			return 2
+			return exitUsage
 		}
-		return 1
+		return exitError
 	}
 
 	client := githubapi.NewClient(opts.BaseURL, token, 30*time.Second, logger)
-
-	repos, err := client.FetchAllRepos(opts.User)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching repositories: %v\n", err)
-		return 1
+	client.Retry.MaxAttempts = opts.MaxAttempts
+	client.Retry.MaxWait = opts.MaxRetryWait
+	if !opts.NoCache {
+		if dir := resolveCacheDir(opts.CacheDir); dir != "" {
+			client.Cache = githubapi.NewDiskCache(dir)
+		} else {
+			logger.Printf("Warning: no cache directory available; caching disabled")
+		}
 	}
 
-	// Filter
-	filtered := core.FilterRepos(repos, core.FilterOptions{
-		IncludeForks:       opts.IncludeForks,
-		IncludeArchived:    opts.IncludeArchived,
-		MinStars:           opts.MinStars,
-		SinceDays:          opts.SinceDays,
-		RequireDescription: opts.RequireDescription,
-		Tags:               opts.Tags,
-		TagsMatchAll:       opts.TagMatch == "all",
-	})
-
-	// Sort
-	core.SortReposBy(filtered, opts.Sort)
+	// Cancel on SIGINT/SIGTERM; --deadline bounds the whole fetch including pagination
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if opts.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Deadline)
+		defer cancel()
+	}
 
-	// Top N
-	filtered = core.TopN(filtered, opts.Top)
+	repos, pinned, err := fetchRepos(ctx, client, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return fetchExitCode(err)
+	}
 
-	// Render
-	var output string
-	switch opts.Format {
-	case "json":
-		output, err = core.RenderJSON(filtered)
+	var tmpl *template.Template
+	if opts.TemplatePath != "" {
+		text, err := os.ReadFile(opts.TemplatePath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error rendering JSON: %v\n", err)
-			return 1
+			fmt.Fprintf(stderr, "Error reading template %q: %v\n", opts.TemplatePath, err)
+			return exitError
+		}
+		tmpl, err = core.ParseMarkdownTemplate(filepath.Base(opts.TemplatePath), string(text))
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return exitUsage
 		}
-	default:
-		output = core.RenderMarkdown(filtered, opts.Marker)
 	}
 
-	// If --readme is specified, patch the existing file
-	if opts.ReadmePath != "" {
-		existing, err := os.ReadFile(opts.ReadmePath)
+	// Render every section from the same fetched repository list
+	now := time.Now()
+	sections := opts.EffectiveSections()
+	outputs := make([]string, len(sections))
+	for i, sec := range sections {
+		outputs[i], err = renderSection(repos, pinned, opts, sec, tmpl, now)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading README %q: %v\n", opts.ReadmePath, err)
-			return 1
+			fmt.Fprintf(stderr, "Error rendering output: %v\n", err)
+			return exitError
 		}
+	}
 
-		result, err := core.PatchREADME(string(existing), output, opts.Marker, opts.AppendIfMissing)
+	// If --readme is specified, patch every section into the existing file
+	if opts.ReadmePath != "" {
+		existing, err := os.ReadFile(opts.ReadmePath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error patching README: %v\n", err)
-			return 1
+			fmt.Fprintf(stderr, "Error reading README %q: %v\n", opts.ReadmePath, err)
+			return exitError
 		}
 
 		outPath := opts.ReadmePath
 		if opts.OutPath != "" {
 			outPath = opts.OutPath
 		}
+		summary := patchSummary{Path: outPath}
+
+		content := string(existing)
+		for i, sec := range sections {
+			result, err := core.PatchREADMEWithOptions(content, outputs[i], core.PatchOptions{
+				Marker:          sec.Marker,
+				AppendIfMissing: opts.AppendIfMissing,
+				Policy:          opts.MarkerPolicy,
+				HTML:            opts.Format == "html",
+				Render: func(attrs map[string]string) (string, error) {
+					inline, err := sec.WithAttributes(attrs)
+					if err != nil {
+						return "", err
+					}
+					return renderSection(repos, pinned, opts, inline, tmpl, now)
+				},
+			})
+			if err != nil {
+				fmt.Fprintf(stderr, "Error patching README: %v\n", err)
+				return exitError
+			}
+			for _, b := range result.Ignored {
+				logger.Printf("Warning: left extra %q block at lines %d-%d unchanged (--marker-policy first)", sec.Marker, b.BeginLine, b.EndLine)
+			}
+			content = result.Content
+			summary.Sections = append(summary.Sections, sectionSummary{Marker: sec.Marker, Changed: result.Changed})
+		}
+
+		// Compare against the file that would be written
+		current, exists := string(existing), true
+		if outPath != opts.ReadmePath {
+			current, exists, err = readIfExists(outPath)
+			if err != nil {
+				fmt.Fprintf(stderr, "Error reading file %q: %v\n", outPath, err)
+				return exitError
+			}
+		}
+		summary.Changed = !exists || content != current
 
-		if err := os.WriteFile(outPath, []byte(result.Content), 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing file %q: %v\n", outPath, err)
-			return 1
+		code := exitOK
+		switch {
+		case opts.Check && summary.Changed:
+			fmt.Fprint(stderr, core.UnifiedDiff(outPath, outPath, current, content))
+			fmt.Fprintf(stderr, "Error: %s is out of date; run without --check to update it\n", outPath)
+			code = exitStale
+		case opts.Check:
+			logger.Printf("README is up to date: %s", outPath)
+		case opts.DryRun && summary.Changed:
+			fmt.Fprint(stdout, core.UnifiedDiff(outPath, outPath, current, content))
+		case opts.DryRun:
+			logger.Printf("Dry run: no changes to %s", outPath)
+		case summary.Changed:
+			if err := writeFileAtomic(outPath, []byte(content), opts.Backup); err != nil {
+				fmt.Fprintf(stderr, "Error writing file %q: %v\n", outPath, err)
+				return exitError
+			}
+			logger.Printf("README updated: %s", outPath)
+		default:
+			logger.Printf("README unchanged, not writing: %s", outPath)
 		}
-		logger.Printf("README updated: %s", outPath)
-		return 0
+
+		if opts.Summary {
+			if err := json.NewEncoder(stdout).Encode(summary); err != nil {
+				fmt.Fprintf(stderr, "Error writing summary: %v\n", err)
+				return exitError
+			}
+		}
+		return code
 	}
 
 	// Write output
+	output := strings.Join(outputs, "\n")
 	if opts.OutPath != "" {
-		if err := os.WriteFile(opts.OutPath, []byte(output), 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing file %q: %v\n", opts.OutPath, err)
-			return 1
+		current, exists, err := readIfExists(opts.OutPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error reading file %q: %v\n", opts.OutPath, err)
+			return exitError
+		}
+		if exists && current == output {
+			logger.Printf("Output unchanged, not writing: %s", opts.OutPath)
+			return exitOK
+		}
+		if opts.DryRun {
+			fmt.Fprint(stdout, core.UnifiedDiff(opts.OutPath, opts.OutPath, current, output))
+			return exitOK
+		}
+		if err := writeFileAtomic(opts.OutPath, []byte(output), opts.Backup); err != nil {
+			fmt.Fprintf(stderr, "Error writing file %q: %v\n", opts.OutPath, err)
+			return exitError
 		}
 		logger.Printf("Output written to: %s", opts.OutPath)
 	} else {
-		fmt.Print(output)
+		fmt.Fprint(stdout, output)
+	}
+
+	return exitOK
+}
+
+// patchSummary is the machine-readable result of a README update, printed
+// with --summary.
+type patchSummary struct {
+	Path     string           `json:"path"`
+	Changed  bool             `json:"changed"`
+	Sections []sectionSummary `json:"sections"`
+}
+
+type sectionSummary struct {
+	Marker  string `json:"marker"`
+	Changed bool   `json:"changed"`
+}
+
+// readIfExists reads path, reporting exists=false instead of an error when
+// the file does not exist.
+func readIfExists(path string) (content string, exists bool, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), true, nil
+}
+
+// renderSection filters, sorts and renders one section of the output.
+// A non-nil tmpl replaces the built-in Markdown layout.
+func renderSection(repos, pinned []githubapi.Repository, opts *cli.Options, sec cli.Section, tmpl *template.Template, now time.Time) (string, error) {
+	// Filter
+	filtered := core.FilterRepos(repos, core.FilterOptions{
+		IncludePrivate:     sec.IncludePrivate,
+		IncludeForks:       sec.IncludeForks,
+		IncludeArchived:    sec.IncludeArchived,
+		MinStars:           sec.MinStars,
+		SinceDays:          sec.SinceDays,
+		RequireDescription: sec.RequireDescription,
+		Tags:               sec.Tags,
+		TagsMatchAll:       sec.TagMatch == "all",
+	})
+
+	// Sort, then force pinned repositories to the top
+	core.SortReposBy(filtered, sec.Sort)
+	filtered, pinnedCount := core.PinFirst(filtered, pinned)
+
+	// Top N. With --group-top the per-group cap replaces it, so every group
+	// is filled from the full filtered list.
+	if opts.GroupBy == "" || opts.GroupTop == 0 {
+		filtered = core.TopN(filtered, sec.Top)
+	}
+	if !opts.PinnedSection {
+		pinnedCount = 0
+	}
+
+	var groups []core.RepoGroup
+	if opts.GroupBy != "" {
+		groups = core.GroupRepos(filtered, core.GroupOptions{
+			By:       opts.GroupBy,
+			Top:      opts.GroupTop,
+			Order:    opts.GroupOrder,
+			Explicit: opts.GroupList,
+		})
+	}
+	filters := core.TemplateFilters{
+		Top:                sec.Top,
+		MinStars:           sec.MinStars,
+		SinceDays:          sec.SinceDays,
+		IncludeForks:       sec.IncludeForks,
+		IncludeArchived:    sec.IncludeArchived,
+		IncludePrivate:     sec.IncludePrivate,
+		RequireDescription: sec.RequireDescription,
+		Topics:             sec.Tags,
+		TagMatch:           sec.TagMatch,
+		Sort:               sec.Sort,
+	}
+	mdOpts := core.MarkdownOptions{
+		Heading:      sec.Heading,
+		PrivateStyle: opts.PrivateStyle,
+		PinnedCount:  pinnedCount,
+	}
+
+	switch {
+	case opts.Format == "json" && opts.JSONLegacy && groups != nil:
+		return core.RenderJSONGroups(groups)
+	case opts.Format == "json" && opts.JSONLegacy:
+		return core.RenderJSON(filtered)
+	case opts.Format == "json":
+		return core.RenderJSONEnvelope(filtered, core.JSONEnvelopeOptions{
+			GeneratedAt: now,
+			User:        opts.User,
+			Filters:     filters,
+			Groups:      groups,
+		})
+	case opts.Format == "markdown-table":
+		return core.RenderMarkdownTable(filtered, sec.Marker, core.TableOptions{
+			MarkdownOptions: mdOpts,
+			Columns:         opts.Columns,
+			Align:           opts.TableAlign,
+		}), nil
+	case opts.Format == "csv":
+		return core.RenderCSV(filtered, opts.Fields)
+	case opts.Format == "tsv":
+		return core.RenderTSV(filtered, opts.Fields)
+	case opts.Format == "jsonl":
+		return core.RenderJSONL(filtered, opts.Fields)
+	case opts.Format == "svg":
+		return core.RenderSVG(filtered, core.SVGOptions{
+			Heading:          sec.Heading,
+			Theme:            opts.SVGTheme,
+			Width:            opts.SVGWidth,
+			DescriptionLines: opts.SVGDescLines,
+		}), nil
+	case opts.Format == "atom" || opts.Format == "rss":
+		feedOpts := core.FeedOptions{
+			Title:        sec.Heading,
+			Author:       opts.User,
+			Link:         opts.FeedLink,
+			SelfURL:      opts.FeedURL,
+			PrivateStyle: opts.PrivateStyle,
+			Now:          now,
+		}
+		if feedOpts.Link == "" {
+			feedOpts.Link = githubapi.ProfileURL(opts.BaseURL, opts.User)
+		}
+		if opts.Format == "atom" {
+			return core.RenderAtom(filtered, feedOpts)
+		}
+		return core.RenderRSS(filtered, feedOpts)
+	case opts.Format == "html":
+		htmlOpts := core.HTMLOptions{
+			MarkdownOptions: mdOpts,
+			Layout:          opts.HTMLLayout,
+			Columns:         opts.Columns,
+			Standalone:      opts.HTMLStandalone,
+		}
+		if groups != nil {
+			return core.RenderHTMLGroups(groups, sec.Marker, htmlOpts)
+		}
+		return core.RenderHTML(filtered, sec.Marker, htmlOpts)
+	case tmpl != nil:
+		return core.RenderMarkdownTemplate(tmpl, core.TemplateData{
+			Repos:        filtered,
+			Groups:       groups,
+			GeneratedAt:  now,
+			User:         opts.User,
+			Heading:      sec.Heading,
+			Marker:       sec.Marker,
+			Filters:      filters,
+			PrivateStyle: opts.PrivateStyle,
+		})
+	case groups != nil:
+		return core.RenderMarkdownGroups(groups, sec.Marker, mdOpts), nil
+	default:
+		return core.RenderMarkdownWithOptions(filtered, sec.Marker, mdOpts), nil
+	}
+}
+
+// fetchRepos collects repositories from every configured source. When --pinned
+// is set it also returns the pinned repositories, in pinned order.
+func fetchRepos(ctx context.Context, client *githubapi.Client, opts *cli.Options) (repos, pinned []githubapi.Repository, err error) {
+	gql := githubapi.NewGraphQLClient(client, opts.GraphQLFields)
+	if opts.Pinned != "none" {
+		pinned, err = gql.FetchPinnedReposContext(ctx, opts.User)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching pinned repositories: %w", err)
+		}
+		if opts.Pinned == "only" {
+			return pinned, pinned, nil
+		}
+	}
+
+	var fetcher githubapi.OrgRepoFetcher = client
+	if opts.API == "graphql" {
+		fetcher = gql
+	}
+
+	if opts.Authenticated {
+		repos, err = client.FetchAuthenticatedReposContext(ctx, opts.Affiliation, opts.Visibility)
+	} else {
+		repos, err = fetcher.FetchAllReposContext(ctx, opts.User)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching repositories: %w", err)
+	}
+
+	// Merge organization repositories, de-duplicated by full name
+	for _, org := range opts.Orgs {
+		orgRepos, err := fetcher.FetchOrgReposContext(ctx, org)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching repositories for org %q: %w", org, err)
+		}
+		repos = core.MergeRepos(repos, orgRepos)
 	}
 
-	return 0
+	return repos, pinned, nil
+}
+
+// resolveCacheDir returns dir, or the per-user cache directory when dir is empty.
+// It returns "" if no directory can be determined.
+func resolveCacheDir(dir string) string {
+	if dir != "" {
+		return dir
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(base, "github-current-projects")
+}
+
+// fetchExitCode maps a githubapi error to its documented exit code.
+func fetchExitCode(err error) int {
+	var (
+		notFound  *githubapi.NotFoundError
+		auth      *githubapi.AuthError
+		rateLimit *githubapi.RateLimitError
+		server    *githubapi.ServerError
+	)
+	switch {
+	case errors.As(err, &notFound):
+		return exitNotFound
+	case errors.As(err, &auth):
+		return exitAuth
+	case errors.As(err, &rateLimit):
+		return exitRateLimit
+	case errors.As(err, &server):
+		return exitServerError
+	default:
+		return exitError
+	}
 }
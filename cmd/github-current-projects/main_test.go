@@ -0,0 +1,474 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestServer serves testdata/repos_page1.json as the repositories of
+// "testuser" and counts the requests it receives.
+func newTestServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	page1, err := os.ReadFile("../../testdata/repos_page1.json")
+	if err != nil {
+		t.Fatalf("reading testdata: %v", err)
+	}
+	return newReposServer(t, page1)
+}
+
+// newReposServer serves page1 as the single page of repositories of
+// "testuser" and counts the requests it receives.
+func newReposServer(t *testing.T, page1 []byte) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/testuser/repos", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(page1); err != nil {
+			t.Errorf("writing page1 response: %v", err)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// runCommand runs the command against server and returns its exit code and
+// output. The token is cleared so the environment cannot affect the run.
+func runCommand(t *testing.T, server *httptest.Server, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	t.Setenv("GITHUB_TOKEN", "")
+
+	var out, errOut bytes.Buffer
+	args = append([]string{"--user", "testuser", "--base-url", server.URL, "--no-cache"}, args...)
+	code = run(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRunMultipleSections(t *testing.T) {
+	server, calls := newTestServer(t)
+	dir := t.TempDir()
+
+	readme := filepath.Join(dir, "README.md")
+	writeTestFile(t, readme, "# Profile\n\n<!-- BEGIN RECENT -->\n<!-- END RECENT -->\n\n<!-- BEGIN STARRED -->\n<!-- END STARRED -->\n\n## About\n")
+
+	config := filepath.Join(dir, "config.json")
+	writeTestFile(t, config, `{
+		"readme": "`+filepath.ToSlash(readme)+`",
+		"sections": [
+			{"marker": "RECENT", "heading": "Recently active"},
+			{"marker": "STARRED", "heading": "Most starred", "sort": "stars", "include-forks": true, "include-archived": true, "top": 2}
+		]
+	}`)
+
+	code, _, stderr := runCommand(t, server, "--config", config)
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected a single API call for both sections, got %d", n)
+	}
+
+	want := "# Profile\n\n" +
+		"<!-- BEGIN RECENT -->\n## Recently active\n\n" +
+		"- [awesome-project](https://github.com/testuser/awesome-project) (Go) - An awesome project\n" +
+		"<!-- END RECENT -->\n\n" +
+		"<!-- BEGIN STARRED -->\n## Most starred\n\n" +
+		"- [awesome-project](https://github.com/testuser/awesome-project) (Go) - An awesome project\n" +
+		"- [archived-repo](https://github.com/testuser/archived-repo) (Rust) - An archived repo\n" +
+		"<!-- END STARRED -->\n\n## About\n"
+	if got := readTestFile(t, readme); got != want {
+		t.Errorf("README mismatch:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunGroupTopReplacesTop(t *testing.T) {
+	// Eleven recent Go repositories and one older Rust repository: a global
+	// --top 10 applied before grouping would drop the Rust group entirely.
+	var repos []map[string]any
+	for i := 0; i < 11; i++ {
+		repos = append(repos, map[string]any{
+			"name":      fmt.Sprintf("go-%02d", i),
+			"full_name": fmt.Sprintf("testuser/go-%02d", i),
+			"html_url":  fmt.Sprintf("https://github.com/testuser/go-%02d", i),
+			"language":  "Go",
+			"pushed_at": fmt.Sprintf("2025-01-%02dT00:00:00Z", 20-i),
+		})
+	}
+	repos = append(repos, map[string]any{
+		"name":      "rusty",
+		"full_name": "testuser/rusty",
+		"html_url":  "https://github.com/testuser/rusty",
+		"language":  "Rust",
+		"pushed_at": "2024-06-01T00:00:00Z",
+	})
+	page, err := json.Marshal(repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, _ := newReposServer(t, page)
+
+	code, stdout, stderr := runCommand(t, server, "--group-by", "language", "--group-top", "2")
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+	}
+	want := "<!-- BEGIN CURRENT PROJECTS -->\n## Current Projects\n\n" +
+		"### Go\n\n" +
+		"- [go-00](https://github.com/testuser/go-00) (Go)\n" +
+		"- [go-01](https://github.com/testuser/go-01) (Go)\n" +
+		"\n### Rust\n\n" +
+		"- [rusty](https://github.com/testuser/rusty) (Rust)\n" +
+		"<!-- END CURRENT PROJECTS -->\n"
+	if stdout != want {
+		t.Errorf("output mismatch:\ngot:\n%s\nwant:\n%s", stdout, want)
+	}
+
+	// Without --group-top, --top still bounds the output before grouping.
+	code, stdout, _ = runCommand(t, server, "--group-by", "language", "--top", "3")
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d", code, exitOK)
+	}
+	if strings.Count(stdout, "- [") != 3 || strings.Contains(stdout, "rusty") {
+		t.Errorf("--top 3 should keep the three most recent repositories:\n%s", stdout)
+	}
+}
+
+const staleReadme = "# Profile\n\n<!-- BEGIN CURRENT PROJECTS -->\n- old\n<!-- END CURRENT PROJECTS -->\n"
+
+// currentReadme is staleReadme patched with testdata/repos_page1.json.
+const currentReadme = "# Profile\n\n<!-- BEGIN CURRENT PROJECTS -->\n## Current Projects\n\n" +
+	"- [awesome-project](https://github.com/testuser/awesome-project) (Go) - An awesome project\n" +
+	"<!-- END CURRENT PROJECTS -->\n"
+
+// pastTime is set as the modification time of fixture files so that any
+// rewrite is detectable.
+var pastTime = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// writeFixture writes content to path and backdates it to pastTime.
+func writeFixture(t *testing.T, path, content string) {
+	t.Helper()
+	writeTestFile(t, path, content)
+	if err := os.Chtimes(path, pastTime, pastTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// assertUntouched fails unless path still holds content with mtime pastTime.
+func assertUntouched(t *testing.T, path, content string) {
+	t.Helper()
+	if got := readTestFile(t, path); got != content {
+		t.Errorf("%s was modified:\n%s", filepath.Base(path), got)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(pastTime) {
+		t.Errorf("%s mtime = %v, want %v (file was rewritten)", filepath.Base(path), info.ModTime(), pastTime)
+	}
+}
+
+func TestRunCheckUpToDate(t *testing.T) {
+	server, _ := newTestServer(t)
+	readme := filepath.Join(t.TempDir(), "README.md")
+	writeFixture(t, readme, currentReadme)
+
+	code, stdout, stderr := runCommand(t, server, "--readme", readme, "--check")
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("stdout = %q, want empty", stdout)
+	}
+	if strings.Contains(stderr, "@@") {
+		t.Errorf("no diff expected for an up-to-date README:\n%s", stderr)
+	}
+	assertUntouched(t, readme, currentReadme)
+}
+
+func TestRunCheckStale(t *testing.T) {
+	server, _ := newTestServer(t)
+	readme := filepath.Join(t.TempDir(), "README.md")
+	writeFixture(t, readme, staleReadme)
+
+	code, stdout, stderr := runCommand(t, server, "--readme", readme, "--check")
+	if code != exitStale {
+		t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitStale, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("stdout = %q, want empty", stdout)
+	}
+	for _, want := range []string{
+		"--- " + readme + "\n+++ " + readme + "\n",
+		"@@ -1,5 +1,7 @@\n",
+		"-- old\n",
+		"+- [awesome-project](https://github.com/testuser/awesome-project) (Go) - An awesome project\n",
+		"is out of date",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("stderr missing %q:\n%s", want, stderr)
+		}
+	}
+	assertUntouched(t, readme, staleReadme)
+}
+
+func TestRunCheckComparesOut(t *testing.T) {
+	server, _ := newTestServer(t)
+	dir := t.TempDir()
+	readme := filepath.Join(dir, "README.md")
+	out := filepath.Join(dir, "OUT.md")
+	writeFixture(t, readme, staleReadme)
+
+	// The source README is stale, but the generated file is current.
+	writeFixture(t, out, currentReadme)
+	code, _, stderr := runCommand(t, server, "--readme", readme, "--out", out, "--check")
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+	}
+	assertUntouched(t, readme, staleReadme)
+	assertUntouched(t, out, currentReadme)
+
+	// A missing output file counts as stale.
+	if err := os.Remove(out); err != nil {
+		t.Fatal(err)
+	}
+	code, _, stderr = runCommand(t, server, "--readme", readme, "--out", out, "--check")
+	if code != exitStale {
+		t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitStale, stderr)
+	}
+	if !strings.Contains(stderr, "+++ "+out+"\n") {
+		t.Errorf("diff should be against the --out file:\n%s", stderr)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("--check must not create %s", out)
+	}
+}
+
+// decodeSummary decodes the single --summary line printed to stdout.
+func decodeSummary(t *testing.T, stdout string) patchSummary {
+	t.Helper()
+	if strings.Count(stdout, "\n") != 1 || !strings.HasSuffix(stdout, "\n") {
+		t.Fatalf("summary should be one JSON line, got %q", stdout)
+	}
+	dec := json.NewDecoder(strings.NewReader(stdout))
+	dec.DisallowUnknownFields()
+	var summary patchSummary
+	if err := dec.Decode(&summary); err != nil {
+		t.Fatalf("decoding summary %q: %v", stdout, err)
+	}
+	return summary
+}
+
+func TestRunSummaryChanged(t *testing.T) {
+	server, _ := newTestServer(t)
+	readme := filepath.Join(t.TempDir(), "README.md")
+	writeFixture(t, readme, staleReadme)
+
+	code, stdout, stderr := runCommand(t, server, "--readme", readme, "--summary")
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+	}
+
+	want := patchSummary{
+		Path:     readme,
+		Changed:  true,
+		Sections: []sectionSummary{{Marker: "CURRENT PROJECTS", Changed: true}},
+	}
+	if got := decodeSummary(t, stdout); !reflect.DeepEqual(got, want) {
+		t.Errorf("summary = %+v, want %+v", got, want)
+	}
+	if got := readTestFile(t, readme); got != currentReadme {
+		t.Errorf("README not updated:\n%s", got)
+	}
+	info, err := os.Stat(readme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ModTime().Equal(pastTime) {
+		t.Error("README mtime should change when the content changes")
+	}
+}
+
+func TestRunSummaryUnchanged(t *testing.T) {
+	server, _ := newTestServer(t)
+	readme := filepath.Join(t.TempDir(), "README.md")
+	writeFixture(t, readme, currentReadme)
+
+	code, stdout, stderr := runCommand(t, server, "--readme", readme, "--summary")
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+	}
+
+	want := patchSummary{
+		Path:     readme,
+		Changed:  false,
+		Sections: []sectionSummary{{Marker: "CURRENT PROJECTS", Changed: false}},
+	}
+	if got := decodeSummary(t, stdout); !reflect.DeepEqual(got, want) {
+		t.Errorf("summary = %+v, want %+v", got, want)
+	}
+	if !strings.Contains(stderr, "README unchanged, not writing") {
+		t.Errorf("expected unchanged log, got:\n%s", stderr)
+	}
+	assertUntouched(t, readme, currentReadme)
+}
+
+func TestRunOutUnchangedNotRewritten(t *testing.T) {
+	server, _ := newTestServer(t)
+	out := filepath.Join(t.TempDir(), "projects.md")
+
+	// The first run creates the file; the second finds it current.
+	if code, _, stderr := runCommand(t, server, "--out", out); code != exitOK {
+		t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+	}
+	generated := readTestFile(t, out)
+	if err := os.Chtimes(out, pastTime, pastTime); err != nil {
+		t.Fatal(err)
+	}
+
+	code, _, stderr := runCommand(t, server, "--out", out)
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+	}
+	if !strings.Contains(stderr, "Output unchanged, not writing") {
+		t.Errorf("expected unchanged log, got:\n%s", stderr)
+	}
+	assertUntouched(t, out, generated)
+}
+
+func TestRunDryRunReadme(t *testing.T) {
+	for _, eol := range []string{"\n", "\r\n"} {
+		t.Run(fmt.Sprintf("eol=%q", eol), func(t *testing.T) {
+			server, _ := newTestServer(t)
+			readme := filepath.Join(t.TempDir(), "README.md")
+			stale := strings.ReplaceAll(staleReadme, "\n", eol)
+			writeFixture(t, readme, stale)
+
+			code, stdout, stderr := runCommand(t, server, "--readme", readme, "--dry-run")
+			if code != exitOK {
+				t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+			}
+
+			want := "--- " + readme + "\n+++ " + readme + "\n@@ -1,5 +1,7 @@\n" +
+				strings.ReplaceAll(" # Profile\n \n <!-- BEGIN CURRENT PROJECTS -->\n-- old\n+## Current Projects\n+\n"+
+					"+- [awesome-project](https://github.com/testuser/awesome-project) (Go) - An awesome project\n"+
+					" <!-- END CURRENT PROJECTS -->\n", "\n", eol)
+			if stdout != want {
+				t.Errorf("stdout = %q, want %q", stdout, want)
+			}
+			assertUntouched(t, readme, stale)
+		})
+	}
+}
+
+func TestRunDryRunOut(t *testing.T) {
+	t.Run("readme and out", func(t *testing.T) {
+		server, _ := newTestServer(t)
+		dir := t.TempDir()
+		readme := filepath.Join(dir, "README.md")
+		out := filepath.Join(dir, "README.generated.md")
+		writeFixture(t, readme, staleReadme)
+
+		code, stdout, stderr := runCommand(t, server, "--readme", readme, "--out", out, "--dry-run")
+		if code != exitOK {
+			t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+		}
+		// out does not exist yet, so the whole patched README is added
+		if !strings.HasPrefix(stdout, "--- "+out+"\n+++ "+out+"\n") || !strings.Contains(stdout, "+# Profile\n") {
+			t.Errorf("expected a diff against %s, got:\n%s", out, stdout)
+		}
+		assertUntouched(t, readme, staleReadme)
+		if _, err := os.Stat(out); !os.IsNotExist(err) {
+			t.Errorf("--dry-run must not create %s", out)
+		}
+	})
+
+	t.Run("out only", func(t *testing.T) {
+		server, _ := newTestServer(t)
+		out := filepath.Join(t.TempDir(), "projects.md")
+		writeFixture(t, out, "- old\r\n")
+
+		code, stdout, stderr := runCommand(t, server, "--out", out, "--dry-run")
+		if code != exitOK {
+			t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+		}
+		if !strings.Contains(stdout, "-- old\r\n") || !strings.Contains(stdout, "+<!-- BEGIN CURRENT PROJECTS -->\n") {
+			t.Errorf("expected a diff of %s, got:\n%s", out, stdout)
+		}
+		assertUntouched(t, out, "- old\r\n")
+	})
+}
+
+func TestRunInlineAttributeCannotIncludePrivate(t *testing.T) {
+	server, _ := newReposServer(t, []byte(`[
+		{"name": "awesome-project", "html_url": "https://github.com/testuser/awesome-project", "language": "Go", "pushed_at": "2025-01-15T10:00:00Z"},
+		{"name": "secret-project", "html_url": "https://github.com/testuser/secret-project", "private": true, "pushed_at": "2025-01-16T10:00:00Z"}
+	]`))
+	readme := filepath.Join(t.TempDir(), "README.md")
+	content := strings.Replace(staleReadme, "CURRENT PROJECTS -->", "CURRENT PROJECTS include-private=true -->", 1)
+	writeFixture(t, readme, content)
+
+	code, stdout, stderr := runCommand(t, server, "--readme", readme)
+	if code != exitError {
+		t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitError, stderr)
+	}
+	if !strings.Contains(stderr, "include-private cannot be set in the README") {
+		t.Errorf("expected include-private error, got:\n%s", stderr)
+	}
+	if strings.Contains(stdout+stderr, "secret-project") {
+		t.Errorf("private repository leaked:\nstdout:\n%s\nstderr:\n%s", stdout, stderr)
+	}
+	assertUntouched(t, readme, content)
+}
+
+func TestRunHTMLIndentedMarkers(t *testing.T) {
+	server, _ := newTestServer(t)
+	page := filepath.Join(t.TempDir(), "index.html")
+	writeFixture(t, page, "<body>\n  <main>\n\n    <!-- BEGIN CURRENT PROJECTS -->\n    <p>old</p>\n    <!-- END CURRENT PROJECTS -->\n\n  </main>\n</body>\n")
+
+	code, _, stderr := runCommand(t, server, "--readme", page, "--format", "html")
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, exitOK, stderr)
+	}
+
+	got := readTestFile(t, page)
+	if !strings.HasPrefix(got, "<body>\n  <main>\n\n    <!-- BEGIN CURRENT PROJECTS -->\n") ||
+		!strings.HasSuffix(got, "<!-- END CURRENT PROJECTS -->\n\n  </main>\n</body>\n") {
+		t.Errorf("block not replaced in place:\n%s", got)
+	}
+	if strings.Count(got, "<!-- BEGIN CURRENT PROJECTS") != 1 || strings.Contains(got, "<p>old</p>") {
+		t.Errorf("expected exactly one updated block:\n%s", got)
+	}
+	if !strings.Contains(got, "awesome-project") {
+		t.Errorf("rendered projects missing:\n%s", got)
+	}
+}
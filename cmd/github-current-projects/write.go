@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic replaces path with data through a temporary file in the
+// same directory and a rename, so a crash never leaves a partially written
+// file. An existing file keeps its permission bits; a new file gets 0644.
+// With backup, the previous content is first saved to path + ".bak".
+func writeFileAtomic(path string, data []byte, backup bool) error {
+	// Write through symlinks instead of replacing them with a regular file
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	mode := os.FileMode(0o644)
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		mode = info.Mode().Perm()
+		if backup {
+			previous, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("reading %s for backup: %w", path, err)
+			}
+			if err := replaceFile(path+".bak", previous, mode); err != nil {
+				return fmt.Errorf("writing backup: %w", err)
+			}
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	return replaceFile(path, data, mode)
+}
+
+// replaceFile atomically writes data to path with the given mode.
+func replaceFile(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		cleanup()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		cleanup()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
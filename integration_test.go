@@ -193,3 +193,64 @@ func TestEndToEndPatchREADME(t *testing.T) {
 		t.Error("old content should be replaced")
 	}
 }
+
+// TestEndToEndPatchREADMEIgnoresCodeBlocks patches a README that documents
+// the markers in code blocks before the real section.
+func TestEndToEndPatchREADMEIgnoresCodeBlocks(t *testing.T) {
+	page1, err := os.ReadFile("testdata/repos_page1.json")
+	if err != nil {
+		t.Fatalf("reading testdata: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/testuser/repos", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(page1); err != nil {
+			t.Errorf("writing page1 response: %v", err)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := githubapi.NewClient(server.URL, "", 5*time.Second, nil)
+	repos, err := client.FetchAllRepos("testuser")
+	if err != nil {
+		t.Fatalf("FetchAllRepos: %v", err)
+	}
+
+	filtered := core.FilterRepos(repos, core.FilterOptions{})
+	core.SortRepos(filtered)
+
+	markdown := core.RenderMarkdown(filtered, "CURRENT PROJECTS")
+
+	readme, err := os.ReadFile("testdata/readme_with_code_markers.md")
+	if err != nil {
+		t.Fatalf("reading readme testdata: %v", err)
+	}
+
+	result, err := core.PatchREADME(string(readme), markdown, "CURRENT PROJECTS", false)
+	if err != nil {
+		t.Fatalf("PatchREADME: %v", err)
+	}
+
+	for _, example := range []string{
+		"```markdown\n<!-- BEGIN CURRENT PROJECTS -->\n<!-- END CURRENT PROJECTS -->\n```\n",
+		"[fenced-example]",
+		"[indented-example]",
+		"<pre>\n&lt;!-- BEGIN CURRENT PROJECTS --&gt;\n<!-- BEGIN CURRENT PROJECTS -->\n<!-- END CURRENT PROJECTS -->\n</pre>\n",
+	} {
+		if !strings.Contains(result.Content, example) {
+			t.Errorf("documented example %q should be left alone", example)
+		}
+	}
+	if !strings.Contains(result.Content, "[awesome-project]") {
+		t.Error("new content should be present")
+	}
+	if strings.Contains(result.Content, "[old-project]") {
+		t.Error("old content should be replaced")
+	}
+	if !strings.HasSuffix(result.Content, "<!-- END CURRENT PROJECTS -->\n\n## About Me\n\nI love coding.\n") {
+		t.Error("footer should follow the patched section")
+	}
+}
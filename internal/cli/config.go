@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// DefaultConfigFile is the config file discovered in the working directory
+// when --config is not given.
+const DefaultConfigFile = ".github-current-projects.json"
+
+// repeatableFlags lists flags that accept a JSON array in the config file.
+var repeatableFlags = map[string]bool{
+	"org":           true,
+	"topics":        true,
+	"graphql-field": true,
+}
+
+// sectionsKey is the config key holding multiple section specs; it has no flag.
+const sectionsKey = "sections"
+
+// applyConfigFile loads a JSON config file whose keys are flag names (without
+// the leading dashes) and applies each value to fs, skipping flags that were
+// set explicitly on the command line. An empty path means DefaultConfigFile,
+// which is optional; an explicit path must exist. The raw "sections" value,
+// if any, is returned for parseSections.
+func applyConfigFile(flags *flag.FlagSet, path string, explicit map[string]bool) (json.RawMessage, error) {
+	required := path != ""
+	if path == "" {
+		path = DefaultConfigFile
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !required && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, &UsageError{Err: fmt.Errorf("reading config file: %w", err)}
+	}
+
+	values, err := parseConfig(data)
+	if err != nil {
+		return nil, &UsageError{Err: fmt.Errorf("config file %s: %w", path, err)}
+	}
+
+	sections := values[sectionsKey]
+	delete(values, sectionsKey)
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == "config" || flags.Lookup(key) == nil {
+			return nil, &UsageError{Err: fmt.Errorf("config file %s: unknown key %q", path, key)}
+		}
+		if explicit[key] {
+			continue
+		}
+		strs, err := configValueStrings(key, values[key])
+		if err != nil {
+			return nil, &UsageError{Err: fmt.Errorf("config file %s: key %q: %w", path, key, err)}
+		}
+		for _, s := range strs {
+			if err := flags.Set(key, s); err != nil {
+				return nil, &UsageError{Err: fmt.Errorf("config file %s: key %q: %w", path, key, err)}
+			}
+		}
+	}
+	return sections, nil
+}
+
+func parseConfig(data []byte) (map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var values map[string]json.RawMessage
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, errors.New("invalid JSON: unexpected data after top-level object")
+	}
+	return values, nil
+}
+
+// configValueStrings converts a raw JSON value into the string form(s)
+// accepted by flag.Value.Set.
+func configValueStrings(key string, raw json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	switch val := v.(type) {
+	case string:
+		return []string{val}, nil
+	case json.Number:
+		return []string{val.String()}, nil
+	case bool:
+		return []string{strconv.FormatBool(val)}, nil
+	case []any:
+		if !repeatableFlags[key] {
+			return nil, errors.New("arrays are only allowed for repeatable options")
+		}
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("array items must be strings, got %T", item)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	case nil:
+		return nil, errors.New("null is not allowed")
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
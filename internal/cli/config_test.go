@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	return path
+}
+
+func TestParseArgsConfigFile(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), `{
+		"user": "fromfile",
+		"top": 5,
+		"include-forks": true,
+		"topics": ["go", "cli"],
+		"sort": "stars"
+	}`)
+
+	opts, err := ParseArgs([]string{"--config", path}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.User != "fromfile" || opts.Top != 5 || !opts.IncludeForks || opts.Sort != "stars" {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+	if len(opts.Tags) != 2 || opts.Tags[0] != "go" || opts.Tags[1] != "cli" {
+		t.Errorf("Tags = %v, want [go cli]", opts.Tags)
+	}
+}
+
+func TestParseArgsFlagsOverrideConfig(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), `{"user": "fromfile", "top": 5, "topics": ["go"], "include-forks": true}`)
+
+	args := []string{"--config", path, "--user", "fromflag", "--topics", "rust", "--include-forks=false"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.User != "fromflag" {
+		t.Errorf("User = %q, want fromflag", opts.User)
+	}
+	if opts.Top != 5 {
+		t.Errorf("Top = %d, want 5 from file", opts.Top)
+	}
+	if len(opts.Tags) != 1 || opts.Tags[0] != "rust" {
+		t.Errorf("Tags = %v, want [rust]", opts.Tags)
+	}
+	if opts.IncludeForks {
+		t.Error("IncludeForks should be overridden by the flag")
+	}
+}
+
+func TestParseArgsConfigUnknownKey(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), `{"user": "u", "tpo": 5}`)
+
+	_, err := ParseArgs([]string{"--config", path}, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+	if !IsUsageError(err) || !strings.Contains(err.Error(), `unknown key "tpo"`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestParseArgsConfigValidation(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	tests := map[string]string{
+		"invalid format":       `{"user": "u", "format": "xml"}`,
+		"array for scalar":     `{"user": "u", "sort": ["stars"]}`,
+		"wrong type":           `{"user": "u", "top": "many"}`,
+		"null value":           `{"user": null}`,
+		"malformed JSON":       `{"user": "u",}`,
+		"config key":           `{"user": "u", "config": "other.json"}`,
+		"non-string in array":  `{"user": "u", "topics": [1]}`,
+		"negative top":         `{"user": "u", "top": -1}`,
+		"token-dependent rule": `{"user": "u", "api": "graphql"}`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfig(t, t.TempDir(), content)
+			_, err := ParseArgs([]string{"--config", path}, &bytes.Buffer{})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !IsUsageError(err) {
+				t.Errorf("expected UsageError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestParseArgsConfigMissingExplicitFile(t *testing.T) {
+	_, err := ParseArgs([]string{"--config", filepath.Join(t.TempDir(), "nope.json")}, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error for missing --config file")
+	}
+}
+
+func TestParseArgsDiscoversDefaultConfig(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, DefaultConfigFile), []byte(`{"user": "discovered"}`), 0o644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatalf("restoring working directory: %v", err)
+		}
+	}()
+
+	opts, err := ParseArgs(nil, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.User != "discovered" {
+		t.Errorf("User = %q, want discovered", opts.User)
+	}
+}
@@ -6,13 +6,23 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"os"
 	"strings"
+	"time"
+
+	"github.com/shinshin86/github-current-projects/internal/core"
 )
 
 // Options holds all CLI options.
 type Options struct {
 	User               string
+	Orgs               []string
 	Token              string
+	Authenticated      bool
+	Affiliation        string
+	Visibility         string
+	IncludePrivate     bool
+	PrivateStyle       string
 	Top                int
 	MinStars           int
 	IncludeForks       bool
@@ -22,15 +32,50 @@ type Options struct {
 	Tags               []string
 	TagMatch           string
 	Sort               string
+	Pinned             string
+	PinnedSection      bool
 	ReadmePath         string
 	OutPath            string
 	Marker             string
+	TemplatePath       string
 	Format             string
+	GroupBy            string
+	GroupTop           int
+	GroupOrder         string
+	GroupList          []string
+	Columns            []string
+	TableAlign         map[string]string
+	HTMLLayout         string
+	HTMLStandalone     bool
+	SVGTheme           string
+	SVGWidth           int
+	SVGDescLines       int
+	JSONLegacy         bool
+	Fields             []string
+	FeedURL            string
+	FeedLink           string
 	BaseURL            string
+	API                string
+	GraphQLFields      []string
 	AppendIfMissing    bool
+	MarkerPolicy       string
+	Check              bool
+	Summary            bool
+	DryRun             bool
+	Backup             bool
+	MaxAttempts        int
+	MaxRetryWait       time.Duration
+	Deadline           time.Duration
+	CacheDir           string
+	NoCache            bool
+	ConfigPath         string
+	// Sections holds multiple section specs from the config file. When empty,
+	// the top-level options describe a single section (see EffectiveSections).
+	Sections []Section
 }
 
-// ParseArgs parses command-line arguments.
+// ParseArgs parses command-line arguments, filling unset flags from the
+// config file (see DefaultConfigFile and --config).
 // Returns options and nil on success.
 // Returns nil and an error on failure, with exit code hint:
 //   - ErrUsage for usage/argument errors (exit 2)
@@ -39,8 +84,22 @@ func ParseArgs(args []string, stderr io.Writer) (*Options, error) {
 	fs.SetOutput(stderr)
 
 	opts := &Options{}
+	fs.StringVar(&opts.ConfigPath, "config", "", "Path to a JSON config file (default: "+DefaultConfigFile+" if present)")
 	fs.StringVar(&opts.User, "user", "", "GitHub username (required)")
+	fs.Func("org", "Also fetch repositories owned by this organization (repeatable)", func(v string) error {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return errors.New("--org must not be empty")
+		}
+		opts.Orgs = append(opts.Orgs, v)
+		return nil
+	})
 	fs.StringVar(&opts.Token, "token", "", "GitHub personal access token")
+	fs.BoolVar(&opts.Authenticated, "authenticated", false, "Fetch the token owner's repos via /user/repos (requires a token)")
+	fs.StringVar(&opts.Affiliation, "affiliation", "owner", "Affiliation for --authenticated: comma-separated owner, collaborator, organization_member")
+	fs.StringVar(&opts.Visibility, "visibility", "all", "Visibility for --authenticated: all, public or private")
+	fs.BoolVar(&opts.IncludePrivate, "include-private", false, "Include private repositories")
+	fs.StringVar(&opts.PrivateStyle, "private-style", "nolink", "How private repos are rendered: nolink or label")
 	fs.IntVar(&opts.Top, "top", 10, "Number of repos to show")
 	fs.IntVar(&opts.MinStars, "min-stars", 0, "Minimum star count")
 	fs.BoolVar(&opts.IncludeForks, "include-forks", false, "Include forked repositories")
@@ -49,6 +108,8 @@ func ParseArgs(args []string, stderr io.Writer) (*Options, error) {
 	fs.BoolVar(&opts.RequireDescription, "require-description", false, "Only include repos with a description")
 	fs.StringVar(&opts.TagMatch, "tag-match", "any", "Topic match mode: any or all")
 	fs.StringVar(&opts.Sort, "sort", "pushed", "Sort order: pushed or stars")
+	fs.StringVar(&opts.Pinned, "pinned", "none", "Pinned repositories: none, top (force to the top) or only (sole source); requires a token")
+	fs.BoolVar(&opts.PinnedSection, "pinned-section", false, "Render pinned repositories in a separate \"Pinned\" subsection")
 	fs.Func("topics", "Filter by GitHub topic (repeatable)", func(v string) error {
 		v = strings.TrimSpace(v)
 		if v == "" {
@@ -60,41 +121,263 @@ func ParseArgs(args []string, stderr io.Writer) (*Options, error) {
 	fs.StringVar(&opts.ReadmePath, "readme", "", "Path to existing README.md for patching")
 	fs.StringVar(&opts.OutPath, "out", "", "Output file path (default: stdout)")
 	fs.StringVar(&opts.Marker, "marker", "CURRENT PROJECTS", "Marker name for README section")
-	fs.StringVar(&opts.Format, "format", "markdown", "Output format: markdown or json")
+	fs.StringVar(&opts.TemplatePath, "template", "", "Path to a Go text/template file for Markdown output")
+	fs.StringVar(&opts.Format, "format", "markdown", "Output format: markdown, markdown-table, html, svg, atom, rss, json, jsonl, csv or tsv")
+	fs.StringVar(&opts.GroupBy, "group-by", "", "Group repos under sub-headings: language, topic, owner or year")
+	fs.IntVar(&opts.GroupTop, "group-top", 0, "Maximum repos per group (0 = no limit)")
+	fs.StringVar(&opts.GroupOrder, "group-order", "count", "Group order: count or alpha (ignored with --group-list)")
+	fs.Func("group-list", "Comma-separated groups to show, in order; others go to \"Other\"", func(v string) error {
+		opts.GroupList = nil
+		for _, g := range strings.Split(v, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				opts.GroupList = append(opts.GroupList, g)
+			}
+		}
+		if len(opts.GroupList) == 0 {
+			return errors.New("--group-list must not be empty")
+		}
+		return nil
+	})
+	fs.Func("columns", "Comma-separated table columns for markdown-table: name, description, language, stars, pushed", func(v string) error {
+		cols, err := core.ParseTableColumns(v)
+		if err != nil {
+			return err
+		}
+		opts.Columns = cols
+		return nil
+	})
+	fs.Func("table-align", "Comma-separated column:alignment pairs for markdown-table, e.g. stars:right (left, center, right)", func(v string) error {
+		align, err := core.ParseTableAlign(v)
+		if err != nil {
+			return err
+		}
+		opts.TableAlign = align
+		return nil
+	})
+	fs.StringVar(&opts.HTMLLayout, "html-layout", "list", "HTML layout for --format html: list or table")
+	fs.BoolVar(&opts.HTMLStandalone, "html-standalone", false, "Render a complete HTML page instead of a fragment")
+	fs.StringVar(&opts.SVGTheme, "svg-theme", core.SVGThemeLight, "SVG card theme: light or dark")
+	fs.IntVar(&opts.SVGWidth, "svg-width", core.DefaultSVGWidth, fmt.Sprintf("SVG card width in pixels (%d-%d)", core.MinSVGWidth, core.MaxSVGWidth))
+	fs.IntVar(&opts.SVGDescLines, "svg-description-lines", core.DefaultSVGDescriptionLines, "Maximum wrapped description lines per repo in the SVG card")
+	fs.BoolVar(&opts.JSONLegacy, "json-legacy", false, "Emit the pre-envelope JSON array instead of the versioned envelope")
+	fs.Func("fields", "Comma-separated fields for csv, tsv and jsonl, e.g. full_name,stargazers_count", func(v string) error {
+		fields, err := core.ParseExportFields(v)
+		if err != nil {
+			return err
+		}
+		opts.Fields = fields
+		return nil
+	})
+	fs.StringVar(&opts.FeedURL, "feed-url", "", "Public URL of the generated Atom/RSS feed (self link and feed ID)")
+	fs.StringVar(&opts.FeedLink, "feed-link", "", "Web page the feed describes (default: the user's GitHub profile)")
 	fs.StringVar(&opts.BaseURL, "base-url", "https://api.github.com", "GitHub API base URL")
+	fs.StringVar(&opts.API, "api", "rest", "API backend: rest or graphql (graphql requires a token)")
+	fs.Func("graphql-field", "Extra GraphQL repository field selection, e.g. 'releases { totalCount }' (repeatable)", func(v string) error {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return errors.New("--graphql-field must not be empty")
+		}
+		if strings.Count(v, "{") != strings.Count(v, "}") {
+			return fmt.Errorf("--graphql-field has unbalanced braces: %q", v)
+		}
+		opts.GraphQLFields = append(opts.GraphQLFields, v)
+		return nil
+	})
 	fs.BoolVar(&opts.AppendIfMissing, "append-if-missing", false, "Append section if markers not found in README")
+	fs.StringVar(&opts.MarkerPolicy, "marker-policy", core.MarkerPolicyError, "How to handle a README with several blocks for one marker: error, first or all")
+	fs.BoolVar(&opts.Check, "check", false, "Verify the README is up to date without writing; exit 7 and print a diff if not")
+	fs.BoolVar(&opts.Backup, "backup", false, "Save the previous content of the written file as <file>.bak")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "Print a unified diff of the changes to --readme or --out instead of writing")
+	fs.BoolVar(&opts.Summary, "summary", false, "Print a JSON summary of the README update (path, changed, sections) to stdout")
+	fs.IntVar(&opts.MaxAttempts, "max-attempts", 3, "Maximum attempts per API request, including the first (1 = no retry)")
+	fs.DurationVar(&opts.Deadline, "deadline", 0, "Overall time limit for fetching, including pagination and retries (0 = no limit)")
+	fs.StringVar(&opts.CacheDir, "cache-dir", "", "Directory for the HTTP response cache (default: user cache dir)")
+	fs.BoolVar(&opts.NoCache, "no-cache", false, "Disable the HTTP response cache")
+	fs.DurationVar(&opts.MaxRetryWait, "max-retry-wait", time.Minute, "Longest wait accepted before a retry (rate-limit resets beyond this fail immediately)")
 
 	if err := fs.Parse(args); err != nil {
 		return nil, &UsageError{Err: err}
 	}
 
+	// Config file values apply only to flags not given on the command line
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	rawSections, err := applyConfigFile(fs, opts.ConfigPath, explicit)
+	if err != nil {
+		return nil, err
+	}
+
 	if opts.User == "" {
 		return nil, &UsageError{Err: errors.New("--user is required")}
 	}
 
-	if opts.Format != "markdown" && opts.Format != "json" {
-		return nil, &UsageError{Err: fmt.Errorf("--format must be 'markdown' or 'json', got %q", opts.Format)}
+	switch opts.Format {
+	case "markdown", "markdown-table", "html", "svg", "atom", "rss", "json", "jsonl", "csv", "tsv":
+	default:
+		return nil, &UsageError{Err: fmt.Errorf("--format must be 'markdown', 'markdown-table', 'html', 'svg', 'atom', 'rss', 'json', 'jsonl', 'csv' or 'tsv', got %q", opts.Format)}
+	}
+
+	if len(opts.Fields) > 0 && opts.Format != "csv" && opts.Format != "tsv" && opts.Format != "jsonl" {
+		return nil, &UsageError{Err: errors.New("--fields requires --format csv, tsv or jsonl")}
+	}
+
+	if opts.JSONLegacy && opts.Format != "json" {
+		return nil, &UsageError{Err: errors.New("--json-legacy requires --format json")}
+	}
+
+	if (opts.FeedURL != "" || opts.FeedLink != "") && opts.Format != "atom" && opts.Format != "rss" {
+		return nil, &UsageError{Err: errors.New("--feed-url and --feed-link require --format atom or rss")}
+	}
+
+	if opts.FeedURL != "" && !isHTTPURL(opts.FeedURL) {
+		return nil, &UsageError{Err: fmt.Errorf("--feed-url must be an absolute http(s) URL, got %q", opts.FeedURL)}
+	}
+
+	if opts.FeedLink != "" && !isHTTPURL(opts.FeedLink) {
+		return nil, &UsageError{Err: fmt.Errorf("--feed-link must be an absolute http(s) URL, got %q", opts.FeedLink)}
+	}
+
+	if opts.HTMLLayout != "list" && opts.HTMLLayout != "table" {
+		return nil, &UsageError{Err: fmt.Errorf("--html-layout must be 'list' or 'table', got %q", opts.HTMLLayout)}
+	}
+
+	if (opts.HTMLLayout != "list" || opts.HTMLStandalone) && opts.Format != "html" {
+		return nil, &UsageError{Err: errors.New("--html-layout and --html-standalone require --format html")}
+	}
+
+	if !core.IsSVGTheme(opts.SVGTheme) {
+		return nil, &UsageError{Err: fmt.Errorf("--svg-theme must be 'light' or 'dark', got %q", opts.SVGTheme)}
+	}
+
+	if opts.SVGWidth < core.MinSVGWidth || opts.SVGWidth > core.MaxSVGWidth {
+		return nil, &UsageError{Err: fmt.Errorf("--svg-width must be between %d and %d, got %d", core.MinSVGWidth, core.MaxSVGWidth, opts.SVGWidth)}
+	}
+
+	if opts.SVGDescLines < 1 {
+		return nil, &UsageError{Err: fmt.Errorf("--svg-description-lines must be at least 1, got %d", opts.SVGDescLines)}
+	}
+
+	if (opts.SVGTheme != core.SVGThemeLight || opts.SVGWidth != core.DefaultSVGWidth || opts.SVGDescLines != core.DefaultSVGDescriptionLines) && opts.Format != "svg" {
+		return nil, &UsageError{Err: errors.New("--svg-theme, --svg-width and --svg-description-lines require --format svg")}
+	}
+
+	if len(opts.TableAlign) > 0 && opts.Format != "markdown-table" {
+		return nil, &UsageError{Err: errors.New("--table-align requires --format markdown-table")}
+	}
+
+	if len(opts.Columns) > 0 && opts.Format != "markdown-table" && opts.HTMLLayout != "table" {
+		return nil, &UsageError{Err: errors.New("--columns requires --format markdown-table or --html-layout table")}
 	}
 
 	if opts.Sort != "pushed" && opts.Sort != "stars" {
 		return nil, &UsageError{Err: fmt.Errorf("--sort must be 'pushed' or 'stars', got %q", opts.Sort)}
 	}
 
+	switch opts.GroupBy {
+	case "", "language", "topic", "owner", "year":
+	default:
+		return nil, &UsageError{Err: fmt.Errorf("--group-by must be 'language', 'topic', 'owner' or 'year', got %q", opts.GroupBy)}
+	}
+
+	if opts.GroupOrder != "count" && opts.GroupOrder != "alpha" {
+		return nil, &UsageError{Err: fmt.Errorf("--group-order must be 'count' or 'alpha', got %q", opts.GroupOrder)}
+	}
+
+	if opts.GroupTop < 0 {
+		return nil, &UsageError{Err: fmt.Errorf("--group-top must be non-negative, got %d", opts.GroupTop)}
+	}
+
+	if opts.GroupBy == "" && (opts.GroupTop > 0 || len(opts.GroupList) > 0) {
+		return nil, &UsageError{Err: errors.New("--group-top and --group-list require --group-by")}
+	}
+
+	if opts.GroupBy != "" && (opts.Format == "markdown-table" || isDocumentFormat(opts.Format)) {
+		return nil, &UsageError{Err: fmt.Errorf("--group-by cannot be used with --format %s", opts.Format)}
+	}
+
+	if opts.GroupBy != "" && opts.PinnedSection {
+		return nil, &UsageError{Err: errors.New("--group-by cannot be used with --pinned-section")}
+	}
+
+	if opts.PinnedSection && isDocumentFormat(opts.Format) {
+		return nil, &UsageError{Err: fmt.Errorf("--pinned-section cannot be used with --format %s", opts.Format)}
+	}
+
+	if opts.API != "rest" && opts.API != "graphql" {
+		return nil, &UsageError{Err: fmt.Errorf("--api must be 'rest' or 'graphql', got %q", opts.API)}
+	}
+
+	if len(opts.GraphQLFields) > 0 && opts.API != "graphql" && opts.Pinned == "none" {
+		return nil, &UsageError{Err: errors.New("--graphql-field requires --api graphql or --pinned")}
+	}
+
+	if opts.Pinned != "none" && opts.Pinned != "top" && opts.Pinned != "only" {
+		return nil, &UsageError{Err: fmt.Errorf("--pinned must be 'none', 'top' or 'only', got %q", opts.Pinned)}
+	}
+
+	if opts.PinnedSection && opts.Pinned == "none" {
+		return nil, &UsageError{Err: errors.New("--pinned-section requires --pinned top or only")}
+	}
+
 	if opts.TagMatch != "any" && opts.TagMatch != "all" {
 		return nil, &UsageError{Err: fmt.Errorf("--tag-match must be 'any' or 'all', got %q", opts.TagMatch)}
 	}
 
+	if opts.Visibility != "all" && opts.Visibility != "public" && opts.Visibility != "private" {
+		return nil, &UsageError{Err: fmt.Errorf("--visibility must be 'all', 'public' or 'private', got %q", opts.Visibility)}
+	}
+
+	if err := validateAffiliation(opts.Affiliation); err != nil {
+		return nil, &UsageError{Err: err}
+	}
+
+	if opts.PrivateStyle != "nolink" && opts.PrivateStyle != "label" {
+		return nil, &UsageError{Err: fmt.Errorf("--private-style must be 'nolink' or 'label', got %q", opts.PrivateStyle)}
+	}
+
+	if !core.IsMarkerPolicy(opts.MarkerPolicy) {
+		return nil, &UsageError{Err: fmt.Errorf("--marker-policy must be 'error', 'first' or 'all', got %q", opts.MarkerPolicy)}
+	}
+
 	if opts.Top < 0 {
 		return nil, &UsageError{Err: fmt.Errorf("--top must be non-negative, got %d", opts.Top)}
 	}
 
-	if err := ValidateOptions(opts, opts.Token); err != nil {
+	if opts.MaxAttempts < 1 {
+		return nil, &UsageError{Err: fmt.Errorf("--max-attempts must be at least 1, got %d", opts.MaxAttempts)}
+	}
+
+	if opts.MaxRetryWait < 0 {
+		return nil, &UsageError{Err: fmt.Errorf("--max-retry-wait must be non-negative, got %s", opts.MaxRetryWait)}
+	}
+
+	if opts.Deadline < 0 {
+		return nil, &UsageError{Err: fmt.Errorf("--deadline must be non-negative, got %s", opts.Deadline)}
+	}
+
+	if rawSections != nil {
+		opts.Sections, err = parseSections(rawSections, opts)
+		if err != nil {
+			return nil, &UsageError{Err: err}
+		}
+	}
+
+	if err := ValidateOptions(opts, ResolveToken(opts.Token)); err != nil {
 		return nil, err
 	}
 
 	return opts, nil
 }
 
+// ResolveToken returns the --token value, or the GITHUB_TOKEN environment
+// variable when the flag is empty.
+func ResolveToken(flagToken string) string {
+	if flagToken != "" {
+		return flagToken
+	}
+	return os.Getenv("GITHUB_TOKEN")
+}
+
 // UsageError indicates a usage/argument error (exit code 2).
 type UsageError struct {
 	Err error
@@ -119,12 +402,81 @@ func ValidateOptions(opts *Options, token string) error {
 	if opts.ReadmePath != "" && opts.Format == "json" {
 		return &UsageError{Err: errors.New("--readme cannot be used with --format json")}
 	}
+	if opts.TemplatePath != "" && opts.Format != "markdown" {
+		return &UsageError{Err: errors.New("--template can only be used with --format markdown")}
+	}
+	if len(opts.Sections) > 0 && opts.Format == "json" {
+		return &UsageError{Err: errors.New("sections cannot be used with --format json")}
+	}
+	if (opts.Check || opts.Summary) && opts.ReadmePath == "" {
+		return &UsageError{Err: errors.New("--check and --summary require --readme")}
+	}
+	if opts.DryRun && opts.ReadmePath == "" && opts.OutPath == "" {
+		return &UsageError{Err: errors.New("--dry-run requires --readme or --out")}
+	}
+	if opts.Backup && opts.ReadmePath == "" && opts.OutPath == "" {
+		return &UsageError{Err: errors.New("--backup requires --readme or --out")}
+	}
+	if opts.DryRun && (opts.Check || opts.Summary) {
+		return &UsageError{Err: errors.New("--dry-run cannot be used with --check or --summary")}
+	}
+	if isDocumentFormat(opts.Format) && (opts.ReadmePath != "" || len(opts.Sections) > 0) {
+		return &UsageError{Err: fmt.Errorf("--format %s cannot be used with --readme or sections", opts.Format)}
+	}
+	if opts.HTMLStandalone && (opts.ReadmePath != "" || len(opts.Sections) > 0) {
+		return &UsageError{Err: errors.New("--html-standalone cannot be used with --readme or sections")}
+	}
+	if opts.Authenticated && token == "" {
+		return &UsageError{Err: errors.New("--authenticated requires a token (--token or GITHUB_TOKEN)")}
+	}
+	if opts.Pinned != "none" && token == "" {
+		return &UsageError{Err: errors.New("--pinned requires a token (--token or GITHUB_TOKEN)")}
+	}
+	if opts.API == "graphql" {
+		if token == "" {
+			return &UsageError{Err: errors.New("--api graphql requires a token (--token or GITHUB_TOKEN)")}
+		}
+		if opts.Authenticated {
+			return &UsageError{Err: errors.New("--authenticated is not supported with --api graphql")}
+		}
+	}
 	if token != "" && !isSecureBaseURL(opts.BaseURL) {
 		return &UsageError{Err: errors.New("--base-url must use https when a token is set (http is allowed only for localhost)")}
 	}
 	return nil
 }
 
+// isDocumentFormat reports whether format produces a complete document
+// (an image, a feed or a data export) rather than a marker-delimited section.
+func isDocumentFormat(format string) bool {
+	switch format {
+	case "svg", "atom", "rss", "jsonl", "csv", "tsv":
+		return true
+	default:
+		return false
+	}
+}
+
+// isHTTPURL reports whether v is an absolute http or https URL.
+func isHTTPURL(v string) bool {
+	u, err := url.Parse(v)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+func validateAffiliation(affiliation string) error {
+	for _, a := range strings.Split(affiliation, ",") {
+		switch strings.TrimSpace(a) {
+		case "owner", "collaborator", "organization_member":
+		default:
+			return fmt.Errorf("--affiliation must be a comma-separated list of 'owner', 'collaborator' or 'organization_member', got %q", affiliation)
+		}
+	}
+	return nil
+}
+
 func isSecureBaseURL(baseURL string) bool {
 	u, err := url.Parse(baseURL)
 	if err != nil || u.Scheme == "" {
@@ -3,6 +3,7 @@ package cli
 import (
 	"bytes"
 	"testing"
+	"time"
 )
 
 func TestParseArgsValid(t *testing.T) {
@@ -175,3 +176,515 @@ func TestValidateOptionsAllowsLocalhostHTTP(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestParseArgsOrgs(t *testing.T) {
+	args := []string{"--user", "u", "--org", "acme", "--org", "widgets"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opts.Orgs) != 2 || opts.Orgs[0] != "acme" || opts.Orgs[1] != "widgets" {
+		t.Errorf("Orgs = %v, want [acme widgets]", opts.Orgs)
+	}
+}
+
+func TestParseArgsEmptyOrg(t *testing.T) {
+	args := []string{"--user", "u", "--org", " "}
+	_, err := ParseArgs(args, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error for empty --org")
+	}
+	if !IsUsageError(err) {
+		t.Errorf("expected UsageError, got %T", err)
+	}
+}
+
+func TestParseArgsAuthenticatedRequiresToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	args := []string{"--user", "u", "--authenticated"}
+	_, err := ParseArgs(args, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error for --authenticated without token")
+	}
+	if !IsUsageError(err) {
+		t.Errorf("expected UsageError, got %T", err)
+	}
+}
+
+func TestParseArgsAuthenticatedTokenFromEnv(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	args := []string{"--user", "u", "--authenticated"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !opts.Authenticated {
+		t.Errorf("expected Authenticated, got %+v", opts)
+	}
+}
+
+func TestParseArgsAuthenticated(t *testing.T) {
+	args := []string{"--user", "u", "--token", "t", "--authenticated", "--affiliation", "owner,collaborator", "--visibility", "private", "--include-private"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !opts.Authenticated || !opts.IncludePrivate {
+		t.Errorf("expected Authenticated and IncludePrivate, got %+v", opts)
+	}
+	if opts.Affiliation != "owner,collaborator" {
+		t.Errorf("Affiliation = %q", opts.Affiliation)
+	}
+	if opts.Visibility != "private" {
+		t.Errorf("Visibility = %q", opts.Visibility)
+	}
+	if opts.PrivateStyle != "nolink" {
+		t.Errorf("PrivateStyle = %q, want default nolink", opts.PrivateStyle)
+	}
+}
+
+func TestParseArgsInvalidVisibility(t *testing.T) {
+	args := []string{"--user", "u", "--visibility", "internal"}
+	_, err := ParseArgs(args, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error for invalid --visibility")
+	}
+	if !IsUsageError(err) {
+		t.Errorf("expected UsageError, got %T", err)
+	}
+}
+
+func TestParseArgsInvalidAffiliation(t *testing.T) {
+	args := []string{"--user", "u", "--affiliation", "owner,friend"}
+	_, err := ParseArgs(args, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error for invalid --affiliation")
+	}
+	if !IsUsageError(err) {
+		t.Errorf("expected UsageError, got %T", err)
+	}
+}
+
+func TestParseArgsRetryFlags(t *testing.T) {
+	args := []string{"--user", "u", "--max-attempts", "5", "--max-retry-wait", "2m"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.MaxAttempts != 5 {
+		t.Errorf("MaxAttempts = %d, want 5", opts.MaxAttempts)
+	}
+	if opts.MaxRetryWait != 2*time.Minute {
+		t.Errorf("MaxRetryWait = %s, want 2m", opts.MaxRetryWait)
+	}
+}
+
+func TestParseArgsInvalidMaxAttempts(t *testing.T) {
+	args := []string{"--user", "u", "--max-attempts", "0"}
+	_, err := ParseArgs(args, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error for --max-attempts 0")
+	}
+	if !IsUsageError(err) {
+		t.Errorf("expected UsageError, got %T", err)
+	}
+}
+
+func TestParseArgsDeadline(t *testing.T) {
+	args := []string{"--user", "u", "--deadline", "90s"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Deadline != 90*time.Second {
+		t.Errorf("Deadline = %s, want 90s", opts.Deadline)
+	}
+}
+
+func TestParseArgsGraphQLRequiresToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	args := []string{"--user", "u", "--api", "graphql"}
+	_, err := ParseArgs(args, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error for --api graphql without token")
+	}
+	if !IsUsageError(err) {
+		t.Errorf("expected UsageError, got %T", err)
+	}
+}
+
+func TestParseArgsGraphQLTokenFromEnv(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	args := []string{"--user", "u", "--api", "graphql"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.API != "graphql" {
+		t.Errorf("API = %q, want graphql", opts.API)
+	}
+}
+
+func TestParseArgsGraphQLFields(t *testing.T) {
+	args := []string{"--user", "u", "--token", "t", "--api", "graphql", "--graphql-field", "releases { totalCount }"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.API != "graphql" || len(opts.GraphQLFields) != 1 {
+		t.Errorf("unexpected options: API=%q GraphQLFields=%v", opts.API, opts.GraphQLFields)
+	}
+}
+
+func TestParseArgsGraphQLFieldRequiresGraphQL(t *testing.T) {
+	args := []string{"--user", "u", "--graphql-field", "forkCount"}
+	_, err := ParseArgs(args, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error for --graphql-field without --api graphql")
+	}
+}
+
+func TestParseArgsPinned(t *testing.T) {
+	args := []string{"--user", "u", "--token", "t", "--pinned", "top", "--pinned-section"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Pinned != "top" || !opts.PinnedSection {
+		t.Errorf("Pinned = %q, PinnedSection = %v", opts.Pinned, opts.PinnedSection)
+	}
+}
+
+func TestParseArgsPinnedRequiresToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	args := []string{"--user", "u", "--pinned", "only"}
+	_, err := ParseArgs(args, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error for --pinned without token")
+	}
+	if !IsUsageError(err) {
+		t.Errorf("expected UsageError, got %T", err)
+	}
+}
+
+func TestParseArgsPinnedTokenFromEnv(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	for _, mode := range []string{"top", "only"} {
+		opts, err := ParseArgs([]string{"--user", "u", "--pinned", mode}, &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("--pinned %s: unexpected error: %v", mode, err)
+		}
+		if opts.Pinned != mode {
+			t.Errorf("Pinned = %q, want %q", opts.Pinned, mode)
+		}
+	}
+}
+
+func TestParseArgsPinnedSectionRequiresPinned(t *testing.T) {
+	args := []string{"--user", "u", "--pinned-section"}
+	_, err := ParseArgs(args, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error for --pinned-section without --pinned")
+	}
+}
+
+func TestParseArgsTemplateWithJSON(t *testing.T) {
+	args := []string{"--user", "u", "--template", "t.tmpl", "--format", "json"}
+	_, err := ParseArgs(args, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error for --template with --format json")
+	}
+	if !IsUsageError(err) {
+		t.Errorf("expected UsageError, got %T", err)
+	}
+}
+
+func TestParseArgsMarkdownTable(t *testing.T) {
+	args := []string{"--user", "u", "--format", "markdown-table", "--columns", "name, stars", "--table-align", "stars:right"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opts.Columns) != 2 || opts.Columns[0] != "name" || opts.Columns[1] != "stars" {
+		t.Errorf("Columns = %v, want [name stars]", opts.Columns)
+	}
+	if opts.TableAlign["stars"] != "right" {
+		t.Errorf("TableAlign = %v", opts.TableAlign)
+	}
+}
+
+func TestParseArgsTableFlagsInvalid(t *testing.T) {
+	tests := [][]string{
+		{"--user", "u", "--format", "markdown-table", "--columns", "name,owner"},
+		{"--user", "u", "--format", "markdown-table", "--columns", "name,name"},
+		{"--user", "u", "--format", "markdown-table", "--table-align", "stars:middle"},
+		{"--user", "u", "--format", "markdown-table", "--table-align", "stars"},
+		{"--user", "u", "--columns", "name"},
+	}
+	for _, args := range tests {
+		_, err := ParseArgs(args, &bytes.Buffer{})
+		if err == nil {
+			t.Errorf("expected error for %v", args)
+			continue
+		}
+		if !IsUsageError(err) {
+			t.Errorf("expected UsageError for %v, got %T", args, err)
+		}
+	}
+}
+
+func TestParseArgsGroupBy(t *testing.T) {
+	args := []string{"--user", "u", "--group-by", "language", "--group-top", "3", "--group-list", "Go, Rust"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.GroupBy != "language" || opts.GroupTop != 3 || opts.GroupOrder != "count" {
+		t.Errorf("unexpected group options: %+v", opts)
+	}
+	if len(opts.GroupList) != 2 || opts.GroupList[0] != "Go" || opts.GroupList[1] != "Rust" {
+		t.Errorf("GroupList = %v, want [Go Rust]", opts.GroupList)
+	}
+}
+
+func TestParseArgsGroupFlagsInvalid(t *testing.T) {
+	tests := [][]string{
+		{"--user", "u", "--group-by", "license"},
+		{"--user", "u", "--group-by", "language", "--group-order", "size"},
+		{"--user", "u", "--group-by", "language", "--group-top", "-1"},
+		{"--user", "u", "--group-top", "2"},
+		{"--user", "u", "--group-list", "Go"},
+		{"--user", "u", "--group-by", "language", "--group-list", " , "},
+		{"--user", "u", "--group-by", "language", "--format", "markdown-table"},
+	}
+	for _, args := range tests {
+		_, err := ParseArgs(args, &bytes.Buffer{})
+		if err == nil {
+			t.Errorf("expected error for %v", args)
+			continue
+		}
+		if !IsUsageError(err) {
+			t.Errorf("expected UsageError for %v, got %T", args, err)
+		}
+	}
+}
+
+func TestParseArgsHTML(t *testing.T) {
+	args := []string{"--user", "u", "--format", "html", "--html-layout", "table", "--columns", "name,stars"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.HTMLLayout != "table" || len(opts.Columns) != 2 {
+		t.Errorf("unexpected HTML options: %+v", opts)
+	}
+}
+
+func TestParseArgsHTMLFlagsInvalid(t *testing.T) {
+	tests := [][]string{
+		{"--user", "u", "--format", "html", "--html-layout", "grid"},
+		{"--user", "u", "--html-standalone"},
+		{"--user", "u", "--html-layout", "table"},
+		{"--user", "u", "--format", "html", "--columns", "name"},
+		{"--user", "u", "--format", "html", "--html-layout", "table", "--table-align", "name:left"},
+		{"--user", "u", "--format", "html", "--html-standalone", "--readme", "index.html"},
+	}
+	for _, args := range tests {
+		_, err := ParseArgs(args, &bytes.Buffer{})
+		if err == nil {
+			t.Errorf("expected error for %v", args)
+			continue
+		}
+		if !IsUsageError(err) {
+			t.Errorf("expected UsageError for %v, got %T", args, err)
+		}
+	}
+}
+
+func TestParseArgsSVG(t *testing.T) {
+	args := []string{"--user", "u", "--format", "svg", "--svg-theme", "dark", "--svg-width", "600"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.SVGTheme != "dark" || opts.SVGWidth != 600 || opts.SVGDescLines != 2 {
+		t.Errorf("unexpected SVG options: %+v", opts)
+	}
+}
+
+func TestParseArgsSVGFlagsInvalid(t *testing.T) {
+	tests := [][]string{
+		{"--user", "u", "--format", "svg", "--svg-theme", "blue"},
+		{"--user", "u", "--format", "svg", "--svg-width", "100"},
+		{"--user", "u", "--format", "svg", "--svg-description-lines", "0"},
+		{"--user", "u", "--svg-theme", "dark"},
+		{"--user", "u", "--format", "svg", "--readme", "README.md"},
+		{"--user", "u", "--format", "svg", "--group-by", "language"},
+	}
+	for _, args := range tests {
+		_, err := ParseArgs(args, &bytes.Buffer{})
+		if err == nil {
+			t.Errorf("expected error for %v", args)
+			continue
+		}
+		if !IsUsageError(err) {
+			t.Errorf("expected UsageError for %v, got %T", args, err)
+		}
+	}
+}
+
+func TestParseArgsFeed(t *testing.T) {
+	args := []string{"--user", "u", "--format", "atom", "--feed-url", "https://u.github.io/projects.atom"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Format != "atom" || opts.FeedURL != "https://u.github.io/projects.atom" {
+		t.Errorf("unexpected feed options: %+v", opts)
+	}
+}
+
+func TestParseArgsFeedFlagsInvalid(t *testing.T) {
+	tests := [][]string{
+		{"--user", "u", "--feed-url", "https://u.github.io/projects.atom"},
+		{"--user", "u", "--format", "rss", "--feed-link", "github.com/u"},
+		{"--user", "u", "--format", "rss", "--feed-url", "javascript:alert(1)"},
+		{"--user", "u", "--format", "atom", "--readme", "README.md"},
+		{"--user", "u", "--format", "rss", "--group-by", "language"},
+	}
+	for _, args := range tests {
+		_, err := ParseArgs(args, &bytes.Buffer{})
+		if err == nil {
+			t.Errorf("expected error for %v", args)
+			continue
+		}
+		if !IsUsageError(err) {
+			t.Errorf("expected UsageError for %v, got %T", args, err)
+		}
+	}
+}
+
+func TestParseArgsJSONLegacyRequiresJSON(t *testing.T) {
+	if _, err := ParseArgs([]string{"--user", "u", "--format", "json", "--json-legacy"}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err := ParseArgs([]string{"--user", "u", "--json-legacy"}, &bytes.Buffer{})
+	if err == nil || !IsUsageError(err) {
+		t.Errorf("expected UsageError, got %v", err)
+	}
+}
+
+func TestParseArgsFields(t *testing.T) {
+	args := []string{"--user", "u", "--format", "csv", "--fields", "full_name, Stargazers_Count"}
+	opts, err := ParseArgs(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opts.Fields) != 2 || opts.Fields[0] != "full_name" || opts.Fields[1] != "stargazers_count" {
+		t.Errorf("Fields = %v, want [full_name stargazers_count]", opts.Fields)
+	}
+}
+
+func TestParseArgsFieldsInvalid(t *testing.T) {
+	tests := [][]string{
+		{"--user", "u", "--format", "csv", "--fields", "name,owner"},
+		{"--user", "u", "--format", "tsv", "--fields", "name,name"},
+		{"--user", "u", "--fields", "name"},
+		{"--user", "u", "--format", "jsonl", "--readme", "README.md"},
+	}
+	for _, args := range tests {
+		_, err := ParseArgs(args, &bytes.Buffer{})
+		if err == nil {
+			t.Errorf("expected error for %v", args)
+			continue
+		}
+		if !IsUsageError(err) {
+			t.Errorf("expected UsageError for %v, got %T", args, err)
+		}
+	}
+}
+
+func TestParseArgsCheckAndSummaryRequireReadme(t *testing.T) {
+	opts, err := ParseArgs([]string{"--user", "u", "--readme", "README.md", "--check"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !opts.Check {
+		t.Error("Check should be true")
+	}
+	for _, flag := range []string{"--check", "--summary"} {
+		_, err = ParseArgs([]string{"--user", "u", flag}, &bytes.Buffer{})
+		if err == nil || !IsUsageError(err) {
+			t.Errorf("%s: expected UsageError, got %v", flag, err)
+		}
+	}
+}
+
+func TestParseArgsDryRun(t *testing.T) {
+	for _, args := range [][]string{
+		{"--user", "u", "--readme", "README.md", "--dry-run"},
+		{"--user", "u", "--out", "out.md", "--dry-run"},
+	} {
+		opts, err := ParseArgs(args, &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("unexpected error for %v: %v", args, err)
+		}
+		if !opts.DryRun {
+			t.Errorf("DryRun should be true for %v", args)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"--user", "u", "--dry-run"},
+		{"--user", "u", "--readme", "README.md", "--dry-run", "--check"},
+		{"--user", "u", "--readme", "README.md", "--dry-run", "--summary"},
+	} {
+		_, err := ParseArgs(args, &bytes.Buffer{})
+		if err == nil || !IsUsageError(err) {
+			t.Errorf("expected UsageError for %v, got %v", args, err)
+		}
+	}
+}
+
+func TestParseArgsBackup(t *testing.T) {
+	for _, args := range [][]string{
+		{"--user", "u", "--readme", "README.md", "--backup"},
+		{"--user", "u", "--out", "out.md", "--backup"},
+	} {
+		opts, err := ParseArgs(args, &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("unexpected error for %v: %v", args, err)
+		}
+		if !opts.Backup {
+			t.Errorf("Backup should be true for %v", args)
+		}
+	}
+
+	_, err := ParseArgs([]string{"--user", "u", "--backup"}, &bytes.Buffer{})
+	if err == nil || !IsUsageError(err) {
+		t.Errorf("expected UsageError, got %v", err)
+	}
+}
+
+func TestParseArgsMarkerPolicy(t *testing.T) {
+	opts, err := ParseArgs([]string{"--user", "u"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.MarkerPolicy != "error" {
+		t.Errorf("default MarkerPolicy = %q, want error", opts.MarkerPolicy)
+	}
+
+	opts, err = ParseArgs([]string{"--user", "u", "--marker-policy", "all"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.MarkerPolicy != "all" {
+		t.Errorf("MarkerPolicy = %q, want all", opts.MarkerPolicy)
+	}
+
+	_, err = ParseArgs([]string{"--user", "u", "--marker-policy", "last"}, &bytes.Buffer{})
+	if err == nil || !IsUsageError(err) {
+		t.Errorf("expected UsageError, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Section describes one generated README section: its marker, heading and
+// the filter, sort and top-N settings applied to the shared repository list.
+// JSON keys match the corresponding flag names.
+type Section struct {
+	Marker             string   `json:"marker"`
+	Heading            string   `json:"heading"`
+	Top                int      `json:"top"`
+	MinStars           int      `json:"min-stars"`
+	IncludeForks       bool     `json:"include-forks"`
+	IncludeArchived    bool     `json:"include-archived"`
+	IncludePrivate     bool     `json:"include-private"`
+	SinceDays          int      `json:"since-days"`
+	RequireDescription bool     `json:"require-description"`
+	Tags               []string `json:"topics"`
+	TagMatch           string   `json:"tag-match"`
+	Sort               string   `json:"sort"`
+}
+
+// EffectiveSections returns the configured sections, or a single section
+// built from the top-level options when none are configured.
+func (o *Options) EffectiveSections() []Section {
+	if len(o.Sections) > 0 {
+		return o.Sections
+	}
+	return []Section{baseSection(o)}
+}
+
+// baseSection copies the section-level settings from the top-level options.
+func baseSection(o *Options) Section {
+	return Section{
+		Marker:             o.Marker,
+		Top:                o.Top,
+		MinStars:           o.MinStars,
+		IncludeForks:       o.IncludeForks,
+		IncludeArchived:    o.IncludeArchived,
+		IncludePrivate:     o.IncludePrivate,
+		SinceDays:          o.SinceDays,
+		RequireDescription: o.RequireDescription,
+		Tags:               append([]string(nil), o.Tags...),
+		TagMatch:           o.TagMatch,
+		Sort:               o.Sort,
+	}
+}
+
+// parseSections decodes the "sections" config value. Each section starts from
+// the top-level options and overrides only the keys it sets; unknown keys are
+// rejected.
+func parseSections(raw json.RawMessage, base *Options) ([]Section, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("sections must be an array of objects: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("sections must not be empty")
+	}
+
+	seen := make(map[string]bool, len(items))
+	sections := make([]Section, 0, len(items))
+	for i, item := range items {
+		sec := baseSection(base)
+		sec.Marker = ""
+
+		dec := json.NewDecoder(bytes.NewReader(item))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&sec); err != nil {
+			return nil, fmt.Errorf("sections[%d]: %w", i, err)
+		}
+		if err := validateSection(sec); err != nil {
+			return nil, fmt.Errorf("sections[%d]: %w", i, err)
+		}
+		if seen[sec.Marker] {
+			return nil, fmt.Errorf("sections[%d]: duplicate marker %q", i, sec.Marker)
+		}
+		seen[sec.Marker] = true
+		sections = append(sections, sec)
+	}
+	return sections, nil
+}
+
+// attributeKeys lists the keys accepted by WithAttributes. include-private is
+// deliberately absent: whoever edits the README must not be able to publish
+// private repositories that the command line or config file did not allow.
+var attributeKeys = []string{
+	"heading", "top", "min-stars", "include-forks", "include-archived",
+	"since-days", "require-description", "topics", "tag-match", "sort",
+}
+
+// WithAttributes returns a copy of sec with the attributes of an inline BEGIN
+// marker applied. Keys match the section config keys; "topics" takes a
+// comma-separated list that replaces the section's topics.
+func (sec Section) WithAttributes(attrs map[string]string) (Section, error) {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	sec.Tags = append([]string(nil), sec.Tags...)
+	for _, k := range keys {
+		v := attrs[k]
+		var err error
+		switch k {
+		case "heading":
+			sec.Heading = v
+		case "top":
+			sec.Top, err = strconv.Atoi(v)
+		case "min-stars":
+			sec.MinStars, err = strconv.Atoi(v)
+		case "since-days":
+			sec.SinceDays, err = strconv.Atoi(v)
+		case "include-forks":
+			sec.IncludeForks, err = strconv.ParseBool(v)
+		case "include-archived":
+			sec.IncludeArchived, err = strconv.ParseBool(v)
+		case "include-private":
+			return Section{}, fmt.Errorf("attribute %s cannot be set in the README; use --include-private or the config file", k)
+		case "require-description":
+			sec.RequireDescription, err = strconv.ParseBool(v)
+		case "topics":
+			sec.Tags = nil
+			for _, t := range strings.Split(v, ",") {
+				if t = strings.TrimSpace(t); t != "" {
+					sec.Tags = append(sec.Tags, t)
+				}
+			}
+		case "tag-match":
+			sec.TagMatch = v
+		case "sort":
+			sec.Sort = v
+		default:
+			return Section{}, fmt.Errorf("unknown attribute %q (valid: %s)", k, strings.Join(attributeKeys, ", "))
+		}
+		if err != nil {
+			return Section{}, fmt.Errorf("attribute %s: invalid value %q", k, v)
+		}
+	}
+
+	if err := validateSection(sec); err != nil {
+		return Section{}, err
+	}
+	return sec, nil
+}
+
+func validateSection(sec Section) error {
+	if strings.TrimSpace(sec.Marker) == "" {
+		return errors.New("marker is required")
+	}
+	if sec.Sort != "pushed" && sec.Sort != "stars" {
+		return fmt.Errorf("sort must be 'pushed' or 'stars', got %q", sec.Sort)
+	}
+	if sec.TagMatch != "any" && sec.TagMatch != "all" {
+		return fmt.Errorf("tag-match must be 'any' or 'all', got %q", sec.TagMatch)
+	}
+	if sec.Top < 0 {
+		return fmt.Errorf("top must be non-negative, got %d", sec.Top)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseArgsSections(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), `{
+		"user": "u",
+		"top": 8,
+		"readme": "README.md",
+		"sections": [
+			{"marker": "RECENT", "heading": "Recently active", "since-days": 30},
+			{"marker": "STARRED", "heading": "Most starred", "sort": "stars", "top": 3},
+			{"marker": "GO", "topics": ["go"]}
+		]
+	}`)
+
+	opts, err := ParseArgs([]string{"--config", path}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opts.Sections) != 3 {
+		t.Fatalf("expected 3 sections, got %d", len(opts.Sections))
+	}
+
+	recent, starred, goSec := opts.Sections[0], opts.Sections[1], opts.Sections[2]
+	if recent.Marker != "RECENT" || recent.Heading != "Recently active" || recent.SinceDays != 30 {
+		t.Errorf("unexpected first section: %+v", recent)
+	}
+	if recent.Top != 8 || recent.Sort != "pushed" {
+		t.Errorf("first section should inherit top-level options: %+v", recent)
+	}
+	if starred.Sort != "stars" || starred.Top != 3 {
+		t.Errorf("unexpected second section: %+v", starred)
+	}
+	if len(goSec.Tags) != 1 || goSec.Tags[0] != "go" {
+		t.Errorf("unexpected third section tags: %v", goSec.Tags)
+	}
+	if got := opts.EffectiveSections(); len(got) != 3 {
+		t.Errorf("EffectiveSections returned %d sections", len(got))
+	}
+}
+
+func TestEffectiveSectionsDefault(t *testing.T) {
+	opts, err := ParseArgs([]string{"--user", "u", "--top", "4", "--marker", "X"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secs := opts.EffectiveSections()
+	if len(secs) != 1 || secs[0].Marker != "X" || secs[0].Top != 4 {
+		t.Errorf("unexpected default section: %+v", secs)
+	}
+}
+
+func TestParseArgsSectionsInvalid(t *testing.T) {
+	tests := map[string]string{
+		"missing marker":   `[{"top": 3}]`,
+		"duplicate marker": `[{"marker": "A"}, {"marker": "A"}]`,
+		"unknown key":      `[{"marker": "A", "colour": "red"}]`,
+		"invalid sort":     `[{"marker": "A", "sort": "name"}]`,
+		"negative top":     `[{"marker": "A", "top": -2}]`,
+		"empty":            `[]`,
+		"not an array":     `{"marker": "A"}`,
+	}
+	for name, sections := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfig(t, t.TempDir(), `{"user": "u", "sections": `+sections+`}`)
+			_, err := ParseArgs([]string{"--config", path}, &bytes.Buffer{})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !IsUsageError(err) {
+				t.Errorf("expected UsageError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestParseArgsSectionsWithJSON(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), `{"user": "u", "format": "json", "sections": [{"marker": "A"}]}`)
+	_, err := ParseArgs([]string{"--config", path}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "sections") {
+		t.Fatalf("expected sections/json error, got %v", err)
+	}
+}
+
+func TestSectionWithAttributes(t *testing.T) {
+	base := Section{Marker: "CURRENT PROJECTS", Top: 8, Sort: "pushed", TagMatch: "any", Tags: []string{"cli"}}
+
+	sec, err := base.WithAttributes(map[string]string{
+		"top":           "5",
+		"sort":          "stars",
+		"topics":        "go, web",
+		"include-forks": "true",
+		"heading":       "Go projects",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sec.Marker != "CURRENT PROJECTS" || sec.Top != 5 || sec.Sort != "stars" || !sec.IncludeForks || sec.Heading != "Go projects" {
+		t.Errorf("unexpected section: %+v", sec)
+	}
+	if strings.Join(sec.Tags, ",") != "go,web" {
+		t.Errorf("Tags = %v, want [go web]", sec.Tags)
+	}
+	if strings.Join(base.Tags, ",") != "cli" {
+		t.Errorf("base section was modified: %v", base.Tags)
+	}
+
+	sec, err = base.WithAttributes(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sec.Top != 8 || sec.Sort != "pushed" {
+		t.Errorf("empty attributes should keep the section: %+v", sec)
+	}
+}
+
+func TestSectionWithAttributesInvalid(t *testing.T) {
+	base := Section{Marker: "CURRENT PROJECTS", Sort: "pushed", TagMatch: "any"}
+
+	for _, tc := range []struct {
+		attrs map[string]string
+		want  string
+	}{
+		{map[string]string{"top": "five"}, "invalid value"},
+		{map[string]string{"include-forks": "maybe"}, "invalid value"},
+		{map[string]string{"top": "-1"}, "non-negative"},
+		{map[string]string{"sort": "name"}, "sort must be"},
+		{map[string]string{"marker": "OTHER"}, `unknown attribute "marker"`},
+		{map[string]string{"format": "html"}, `unknown attribute "format"`},
+		{map[string]string{"include-private": "true"}, "cannot be set in the README"},
+		{map[string]string{"include-private": "false"}, "cannot be set in the README"},
+	} {
+		_, err := base.WithAttributes(tc.attrs)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("WithAttributes(%v) error = %v, want %q", tc.attrs, err, tc.want)
+		}
+	}
+}
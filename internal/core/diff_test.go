@@ -0,0 +1,72 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiffEqual(t *testing.T) {
+	if d := UnifiedDiff("a", "b", "same\n", "same\n"); d != "" {
+		t.Errorf("expected no diff, got:\n%s", d)
+	}
+}
+
+func TestUnifiedDiffChange(t *testing.T) {
+	old := "# Title\n\n<!-- BEGIN X -->\n- old\n<!-- END X -->\n\nFooter\n"
+	new := "# Title\n\n<!-- BEGIN X -->\n- new\n- another\n<!-- END X -->\n\nFooter\n"
+
+	expected := "--- a/README.md\n" +
+		"+++ b/README.md\n" +
+		"@@ -1,7 +1,8 @@\n" +
+		" # Title\n" +
+		" \n" +
+		" <!-- BEGIN X -->\n" +
+		"-- old\n" +
+		"+- new\n" +
+		"+- another\n" +
+		" <!-- END X -->\n" +
+		" \n" +
+		" Footer\n"
+	if d := UnifiedDiff("a/README.md", "b/README.md", old, new); d != expected {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", d, expected)
+	}
+}
+
+func TestUnifiedDiffSeparateHunks(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 1; i <= 20; i++ {
+		oldLines = append(oldLines, fmt.Sprintf("line %d", i))
+		newLines = append(newLines, fmt.Sprintf("line %d", i))
+	}
+	newLines[1] = "changed 2"
+	newLines[17] = "changed 18"
+
+	d := UnifiedDiff("a", "b", strings.Join(oldLines, "\n")+"\n", strings.Join(newLines, "\n")+"\n")
+
+	if strings.Count(d, "@@ ") != 2 {
+		t.Fatalf("expected two hunks:\n%s", d)
+	}
+	if !strings.Contains(d, "@@ -1,5 +1,5 @@\n") || !strings.Contains(d, "@@ -15,6 +15,6 @@\n") {
+		t.Errorf("unexpected hunk headers:\n%s", d)
+	}
+}
+
+func TestUnifiedDiffInsertIntoEmpty(t *testing.T) {
+	d := UnifiedDiff("a", "b", "", "x\n")
+	if d != "--- a\n+++ b\n@@ -0,0 +1 @@\n+x\n" {
+		t.Errorf("unexpected diff:\n%q", d)
+	}
+}
+
+func TestUnifiedDiffLineEndings(t *testing.T) {
+	d := UnifiedDiff("a", "b", "x\r\ny\r\n", "x\r\nz\r\n")
+	if !strings.Contains(d, "-y\r\n+z\r\n") || strings.Contains(d, "-x") {
+		t.Errorf("CRLF lines should be compared and printed intact:\n%q", d)
+	}
+
+	d = UnifiedDiff("a", "b", "x", "x\n")
+	if !strings.Contains(d, "-x\n\\ No newline at end of file\n+x\n") {
+		t.Errorf("missing no-newline marker:\n%q", d)
+	}
+}
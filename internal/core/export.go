@@ -0,0 +1,176 @@
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+// ExportFields lists the fields supported by RenderCSV, RenderTSV and
+// RenderJSONL, in default order. Names match the JSON envelope.
+var ExportFields = []string{
+	"name",
+	"full_name",
+	"html_url",
+	"description",
+	"topics",
+	"fork",
+	"archived",
+	"private",
+	"language",
+	"stargazers_count",
+	"pushed_at",
+}
+
+// IsExportField reports whether name is a supported export field.
+func IsExportField(name string) bool {
+	for _, f := range ExportFields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseExportFields parses a comma-separated list of export fields,
+// rejecting unknown and repeated names.
+func ParseExportFields(v string) ([]string, error) {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, f := range strings.Split(v, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if !IsExportField(f) {
+			return nil, fmt.Errorf("unknown field %q (valid: %s)", f, strings.Join(ExportFields, ", "))
+		}
+		if seen[f] {
+			return nil, fmt.Errorf("duplicate field %q", f)
+		}
+		seen[f] = true
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// RenderCSV produces RFC 4180 CSV with a header row and one row per repo.
+// Empty fields means ExportFields. Topics are joined with ";".
+func RenderCSV(repos []githubapi.Repository, fields []string) (string, error) {
+	return renderDelimited(repos, fields, ',')
+}
+
+// RenderTSV is like RenderCSV but separates fields with tabs.
+func RenderTSV(repos []githubapi.Repository, fields []string) (string, error) {
+	return renderDelimited(repos, fields, '\t')
+}
+
+func renderDelimited(repos []githubapi.Repository, fields []string, comma rune) (string, error) {
+	if len(fields) == 0 {
+		fields = ExportFields
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = comma
+	if err := w.Write(fields); err != nil {
+		return "", fmt.Errorf("writing header: %w", err)
+	}
+	for _, r := range toJSONRepos(repos) {
+		record := make([]string, len(fields))
+		for i, f := range fields {
+			record[i] = exportCell(exportValue(r, f))
+		}
+		if err := w.Write(record); err != nil {
+			return "", fmt.Errorf("writing record: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("writing records: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// RenderJSONL produces JSON Lines: one compact object per repo with the
+// selected fields in the given order. Empty fields means ExportFields.
+func RenderJSONL(repos []githubapi.Repository, fields []string) (string, error) {
+	if len(fields) == 0 {
+		fields = ExportFields
+	}
+
+	var sb strings.Builder
+	for _, r := range toJSONRepos(repos) {
+		sb.WriteByte('{')
+		for i, f := range fields {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			key, err := json.Marshal(f)
+			if err != nil {
+				return "", fmt.Errorf("marshaling JSON: %w", err)
+			}
+			value, err := json.Marshal(exportValue(r, f))
+			if err != nil {
+				return "", fmt.Errorf("marshaling JSON: %w", err)
+			}
+			sb.Write(key)
+			sb.WriteByte(':')
+			sb.Write(value)
+		}
+		sb.WriteString("}\n")
+	}
+	return sb.String(), nil
+}
+
+// exportValue returns the value of field, typed as in the JSON envelope.
+func exportValue(r JSONRepo, field string) any {
+	switch field {
+	case "name":
+		return r.Name
+	case "full_name":
+		return r.FullName
+	case "html_url":
+		return r.HTMLURL
+	case "description":
+		return r.Description
+	case "topics":
+		return r.Topics
+	case "fork":
+		return r.Fork
+	case "archived":
+		return r.Archived
+	case "private":
+		return r.Private
+	case "language":
+		return r.Language
+	case "stargazers_count":
+		return r.StargazersCount
+	case "pushed_at":
+		return r.PushedAt
+	default:
+		return nil
+	}
+}
+
+func exportCell(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case []string:
+		return strings.Join(v, ";")
+	default:
+		return ""
+	}
+}
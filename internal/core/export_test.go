@@ -0,0 +1,121 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+func exportTestRepos() []githubapi.Repository {
+	return []githubapi.Repository{
+		{
+			Name:            "awesome",
+			FullName:        "u/awesome",
+			HTMLURL:         "https://github.com/u/awesome",
+			Description:     "Fast, \"simple\"\nand small",
+			Topics:          []string{"cli", "go"},
+			Archived:        true,
+			Language:        "Go",
+			StargazersCount: 42,
+			PushedAt:        time.Date(2025, 1, 15, 19, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+		},
+		{Name: "bare"},
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	result, err := RenderCSV(exportTestRepos(), nil)
+	if err != nil {
+		t.Fatalf("RenderCSV: %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(result)).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v\n%s", err, result)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(ExportFields, ",") {
+		t.Errorf("header = %v", records[0])
+	}
+	want := []string{
+		"awesome", "u/awesome", "https://github.com/u/awesome", "Fast, \"simple\"\nand small",
+		"cli;go", "false", "true", "false", "Go", "42", "2025-01-15T10:00:00Z",
+	}
+	if strings.Join(records[1], "|") != strings.Join(want, "|") {
+		t.Errorf("row = %q, want %q", records[1], want)
+	}
+	if records[2][10] != "" || records[2][5] != "false" {
+		t.Errorf("unexpected row for bare repo: %q", records[2])
+	}
+	if !strings.Contains(result, `"Fast, ""simple""`) {
+		t.Errorf("expected RFC 4180 quoting:\n%s", result)
+	}
+}
+
+func TestRenderTSVFields(t *testing.T) {
+	result, err := RenderTSV(exportTestRepos(), []string{"stargazers_count", "name"})
+	if err != nil {
+		t.Fatalf("RenderTSV: %v", err)
+	}
+
+	expected := "stargazers_count\tname\n42\tawesome\n0\tbare\n"
+	if result != expected {
+		t.Errorf("unexpected TSV:\n%q\nwant:\n%q", result, expected)
+	}
+}
+
+func TestRenderJSONL(t *testing.T) {
+	result, err := RenderJSONL(exportTestRepos(), []string{"full_name", "topics", "archived", "pushed_at"})
+	if err != nil {
+		t.Fatalf("RenderJSONL: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(result, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), result)
+	}
+	if lines[0] != `{"full_name":"u/awesome","topics":["cli","go"],"archived":true,"pushed_at":"2025-01-15T10:00:00Z"}` {
+		t.Errorf("unexpected first line: %s", lines[0])
+	}
+	if lines[1] != `{"full_name":"","topics":[],"archived":false,"pushed_at":null}` {
+		t.Errorf("unexpected second line: %s", lines[1])
+	}
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("invalid JSON line: %s", line)
+		}
+	}
+}
+
+func TestRenderExportEmpty(t *testing.T) {
+	result, err := RenderCSV(nil, []string{"name"})
+	if err != nil || result != "name\n" {
+		t.Errorf("RenderCSV(nil) = %q, %v; want header only", result, err)
+	}
+	result, err = RenderJSONL(nil, nil)
+	if err != nil || result != "" {
+		t.Errorf("RenderJSONL(nil) = %q, %v; want empty", result, err)
+	}
+}
+
+func TestParseExportFields(t *testing.T) {
+	fields, err := ParseExportFields("name, Stargazers_Count")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(fields, ",") != "name,stargazers_count" {
+		t.Errorf("fields = %v, want [name stargazers_count]", fields)
+	}
+
+	for _, v := range []string{"name,owner", "name,name"} {
+		if _, err := ParseExportFields(v); err == nil {
+			t.Errorf("ParseExportFields(%q): expected error", v)
+		}
+	}
+}
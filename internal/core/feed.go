@@ -0,0 +1,257 @@
+package core
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+// FeedOptions controls Atom and RSS feed rendering.
+type FeedOptions struct {
+	// Title is the feed title. Empty means "Current Projects".
+	Title string
+	// Author names the feed author, typically the GitHub user.
+	Author string
+	// Link is the web page the feed describes, e.g. the GitHub profile.
+	Link string
+	// SelfURL is where the feed itself is published. When set it is also
+	// used as the feed ID.
+	SelfURL string
+	// PrivateStyle selects whether private repositories keep their links.
+	// Empty means PrivateStyleNoLink.
+	PrivateStyle string
+	// Now is used when no repository has a push time. Zero means time.Now().
+	Now time.Time
+}
+
+// feedIDPrefix is the tag URI prefix for entry IDs. Entries are keyed by
+// full name so feed readers keep tracking a repository across renders.
+const feedIDPrefix = "tag:github.com,2008:"
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomPerson  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Links      []atomLink     `xml:"link"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    *atomContent   `xml:"content,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr,omitempty"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	SelfLink      *atomLink `xml:"atom:link,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RenderAtom produces an Atom 1.0 feed with one entry per repo.
+func RenderAtom(repos []githubapi.Repository, opts FeedOptions) (string, error) {
+	title := feedTitle(opts)
+	updated := feedUpdated(repos, opts)
+
+	feed := atomFeed{
+		ID:      opts.SelfURL,
+		Title:   title,
+		Updated: updated.Format(time.RFC3339),
+		Author:  atomPerson{Name: opts.Author},
+	}
+	if feed.ID == "" {
+		feed.ID = feedIDPrefix + "current-projects/" + opts.Author
+	}
+	if link := sanitizeMarkdownURL(opts.Link); link != "" {
+		feed.Links = append(feed.Links, atomLink{Rel: "alternate", Type: "text/html", Href: link})
+	}
+	if self := sanitizeMarkdownURL(opts.SelfURL); self != "" {
+		feed.Links = append(feed.Links, atomLink{Rel: "self", Type: "application/atom+xml", Href: self})
+	}
+
+	for _, r := range repos {
+		pushed := r.PushedAt
+		if pushed.IsZero() {
+			pushed = updated
+		}
+		entry := atomEntry{
+			ID:      feedIDPrefix + feedRepoKey(r),
+			Title:   feedEntryTitle(r),
+			Updated: pushed.UTC().Format(time.RFC3339),
+			Summary: normalizeInlineText(r.Description),
+		}
+		if link := feedEntryLink(r, opts); link != "" {
+			entry.Links = []atomLink{{Rel: "alternate", Type: "text/html", Href: link}}
+		} else {
+			// RFC 4287 requires content when an entry has no alternate link.
+			text := entry.Summary
+			if text == "" {
+				text = entry.Title
+			}
+			entry.Content = &atomContent{Type: "text", Text: text}
+		}
+		for _, term := range feedCategories(r) {
+			entry.Categories = append(entry.Categories, atomCategory{Term: term})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return marshalFeed(feed)
+}
+
+// RenderRSS produces an RSS 2.0 feed with one item per repo.
+func RenderRSS(repos []githubapi.Repository, opts FeedOptions) (string, error) {
+	title := feedTitle(opts)
+	updated := feedUpdated(repos, opts)
+
+	channel := rssChannel{
+		Title:         title,
+		Link:          sanitizeMarkdownURL(opts.Link),
+		Description:   title,
+		LastBuildDate: updated.Format(time.RFC1123Z),
+	}
+	if opts.Author != "" {
+		channel.Description = fmt.Sprintf("%s by %s", title, opts.Author)
+	}
+	feed := rssFeed{Version: "2.0", Channel: channel}
+	if self := sanitizeMarkdownURL(opts.SelfURL); self != "" {
+		feed.Atom = "http://www.w3.org/2005/Atom"
+		feed.Channel.SelfLink = &atomLink{Rel: "self", Type: "application/rss+xml", Href: self}
+	}
+
+	for _, r := range repos {
+		item := rssItem{
+			Title:       feedEntryTitle(r),
+			Link:        feedEntryLink(r, opts),
+			GUID:        rssGUID{IsPermaLink: "false", Value: feedRepoKey(r)},
+			Description: normalizeInlineText(r.Description),
+			Categories:  feedCategories(r),
+		}
+		if !r.PushedAt.IsZero() {
+			item.PubDate = r.PushedAt.UTC().Format(time.RFC1123Z)
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+
+	return marshalFeed(feed)
+}
+
+func feedTitle(opts FeedOptions) string {
+	if title := normalizeInlineText(opts.Title); title != "" {
+		return title
+	}
+	return "Current Projects"
+}
+
+// feedUpdated returns the latest push time among repos, falling back to
+// opts.Now, so the feed only changes when a repository does.
+func feedUpdated(repos []githubapi.Repository, opts FeedOptions) time.Time {
+	var latest time.Time
+	for _, r := range repos {
+		if r.PushedAt.After(latest) {
+			latest = r.PushedAt
+		}
+	}
+	if latest.IsZero() {
+		latest = opts.Now
+		if latest.IsZero() {
+			latest = time.Now()
+		}
+	}
+	return latest.UTC()
+}
+
+// feedRepoKey returns the stable key of a repository entry: its full name,
+// or its name when the full name is unknown.
+func feedRepoKey(r githubapi.Repository) string {
+	if r.FullName != "" {
+		return r.FullName
+	}
+	return r.Name
+}
+
+func feedEntryTitle(r githubapi.Repository) string {
+	title := strings.TrimSpace(r.Name)
+	if r.Private {
+		title += " (private)"
+	}
+	return title
+}
+
+func feedEntryLink(r githubapi.Repository, opts FeedOptions) string {
+	return repoLink(r, opts.PrivateStyle)
+}
+
+// feedCategories returns the language and topics of a repository.
+func feedCategories(r githubapi.Repository) []string {
+	var terms []string
+	if lang := strings.TrimSpace(r.Language); lang != "" {
+		terms = append(terms, lang)
+	}
+	for _, t := range r.Topics {
+		if t = strings.TrimSpace(t); t != "" {
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+func marshalFeed(v any) (string, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling feed: %w", err)
+	}
+	return xml.Header + string(data) + "\n", nil
+}
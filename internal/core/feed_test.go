@@ -0,0 +1,177 @@
+package core
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+func feedTestRepos() []githubapi.Repository {
+	return []githubapi.Repository{
+		{
+			Name:        "awesome",
+			FullName:    "u/awesome",
+			HTMLURL:     "https://github.com/u/awesome",
+			Description: "An <awesome> project",
+			Language:    "Go",
+			Topics:      []string{"cli"},
+			PushedAt:    time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:     "secret",
+			FullName: "u/secret",
+			HTMLURL:  "https://github.com/u/secret",
+			Private:  true,
+			PushedAt: time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestRenderAtom(t *testing.T) {
+	result, err := RenderAtom(feedTestRepos(), FeedOptions{
+		Author:  "u",
+		Link:    "https://github.com/u",
+		SelfURL: "https://u.github.io/projects.atom",
+	})
+	if err != nil {
+		t.Fatalf("RenderAtom: %v", err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal([]byte(result), &feed); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, result)
+	}
+	if feed.XMLName.Space != "http://www.w3.org/2005/Atom" {
+		t.Errorf("namespace = %q", feed.XMLName.Space)
+	}
+	if feed.ID != "https://u.github.io/projects.atom" || feed.Title != "Current Projects" || feed.Author.Name != "u" {
+		t.Errorf("unexpected feed metadata: %+v", feed)
+	}
+	if feed.Updated != "2025-01-15T10:00:00Z" {
+		t.Errorf("feed updated = %q, want latest push", feed.Updated)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(feed.Entries))
+	}
+
+	e := feed.Entries[0]
+	if e.ID != "tag:github.com,2008:u/awesome" || e.Updated != "2025-01-15T10:00:00Z" || e.Summary != "An <awesome> project" {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if len(e.Links) != 1 || e.Links[0].Href != "https://github.com/u/awesome" {
+		t.Errorf("unexpected entry links: %+v", e.Links)
+	}
+	if len(e.Categories) != 2 || e.Categories[0].Term != "Go" || e.Categories[1].Term != "cli" {
+		t.Errorf("unexpected categories: %+v", e.Categories)
+	}
+
+	private := feed.Entries[1]
+	if private.Title != "secret (private)" || len(private.Links) != 0 {
+		t.Errorf("private entry should be labeled without link: %+v", private)
+	}
+}
+
+// TestRenderAtomPrivateEntryHasContent checks RFC 4287 §4.1.1: an entry
+// without an alternate link must carry content.
+func TestRenderAtomPrivateEntryHasContent(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "secret", FullName: "u/secret", HTMLURL: "https://github.com/u/secret", Private: true},
+		{Name: "hidden", FullName: "u/hidden", HTMLURL: "https://github.com/u/hidden", Private: true, Description: "Internal tool"},
+		{Name: "public", FullName: "u/public", HTMLURL: "https://github.com/u/public"},
+	}
+	result, err := RenderAtom(repos, FeedOptions{Author: "u", Now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("RenderAtom: %v", err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal([]byte(result), &feed); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, result)
+	}
+	for _, e := range feed.Entries {
+		if len(e.Links) == 0 && (e.Content == nil || e.Content.Text == "") {
+			t.Errorf("entry %q has neither an alternate link nor content", e.Title)
+		}
+	}
+	if c := feed.Entries[0].Content; c == nil || c.Type != "text" || c.Text != "secret (private)" {
+		t.Errorf("content without description = %+v, want the title", c)
+	}
+	if c := feed.Entries[1].Content; c == nil || c.Text != "Internal tool" {
+		t.Errorf("content with description = %+v, want the description", c)
+	}
+	if feed.Entries[2].Content != nil {
+		t.Errorf("linked entry should not need content: %+v", feed.Entries[2].Content)
+	}
+
+	// With the label style private entries keep their link instead.
+	result, err = RenderAtom(repos[:1], FeedOptions{Author: "u", PrivateStyle: PrivateStyleLabel})
+	if err != nil {
+		t.Fatalf("RenderAtom: %v", err)
+	}
+	if strings.Contains(result, "<content") {
+		t.Errorf("labeled private entry should use its link: %s", result)
+	}
+}
+
+func TestRenderAtomStableAcrossRuns(t *testing.T) {
+	first, err := RenderAtom(feedTestRepos(), FeedOptions{Author: "u", Now: time.Now()})
+	if err != nil {
+		t.Fatalf("RenderAtom: %v", err)
+	}
+	second, err := RenderAtom(feedTestRepos(), FeedOptions{Author: "u", Now: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("RenderAtom: %v", err)
+	}
+	if first != second {
+		t.Errorf("feed should depend only on the repos:\n%s\n%s", first, second)
+	}
+	if !strings.Contains(first, "<id>tag:github.com,2008:current-projects/u</id>") {
+		t.Errorf("expected default feed ID:\n%s", first)
+	}
+}
+
+func TestRenderRSS(t *testing.T) {
+	result, err := RenderRSS(feedTestRepos(), FeedOptions{
+		Title:   "Team Projects",
+		Author:  "u",
+		Link:    "https://github.com/u",
+		SelfURL: "https://u.github.io/projects.xml",
+	})
+	if err != nil {
+		t.Fatalf("RenderRSS: %v", err)
+	}
+
+	var feed rssFeed
+	if err := xml.Unmarshal([]byte(result), &feed); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, result)
+	}
+	if feed.Version != "2.0" || feed.Channel.Title != "Team Projects" || feed.Channel.Description != "Team Projects by u" {
+		t.Errorf("unexpected channel: %+v", feed.Channel)
+	}
+	if !strings.Contains(result, "<link>https://github.com/u</link>") {
+		t.Errorf("missing channel link:\n%s", result)
+	}
+	if feed.Channel.LastBuildDate != "Wed, 15 Jan 2025 10:00:00 +0000" {
+		t.Errorf("lastBuildDate = %q", feed.Channel.LastBuildDate)
+	}
+	if !strings.Contains(result, `<atom:link rel="self" type="application/rss+xml" href="https://u.github.io/projects.xml"></atom:link>`) {
+		t.Errorf("missing self link:\n%s", result)
+	}
+	if len(feed.Channel.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(feed.Channel.Items))
+	}
+
+	item := feed.Channel.Items[0]
+	if item.GUID.Value != "u/awesome" || item.GUID.IsPermaLink != "false" {
+		t.Errorf("unexpected guid: %+v", item.GUID)
+	}
+	if item.PubDate != "Wed, 15 Jan 2025 10:00:00 +0000" || item.Link != "https://github.com/u/awesome" {
+		t.Errorf("unexpected item: %+v", item)
+	}
+	if feed.Channel.Items[1].Link != "" {
+		t.Errorf("private item should have no link: %+v", feed.Channel.Items[1])
+	}
+}
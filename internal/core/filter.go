@@ -9,6 +9,7 @@ import (
 
 // FilterOptions controls which repositories pass filtering.
 type FilterOptions struct {
+	IncludePrivate     bool
 	IncludeForks       bool
 	IncludeArchived    bool
 	MinStars           int
@@ -28,7 +29,7 @@ func FilterRepos(repos []githubapi.Repository, opts FilterOptions) []githubapi.R
 
 	var result []githubapi.Repository
 	for _, r := range repos {
-		if r.Private {
+		if r.Private && !opts.IncludePrivate {
 			continue
 		}
 		if r.Fork && !opts.IncludeForks {
@@ -154,6 +154,17 @@ func TestFilterExcludesPrivate(t *testing.T) {
 	}
 }
 
+func TestFilterIncludePrivate(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "private", Private: true},
+		{Name: "public", Private: false},
+	}
+	filtered := FilterRepos(repos, FilterOptions{IncludePrivate: true})
+	if len(filtered) != 2 {
+		t.Errorf("expected 2 repos, got %d", len(filtered))
+	}
+}
+
 func TestFilterRequireDescription(t *testing.T) {
 	repos := []githubapi.Repository{
 		{Name: "no-desc", Description: ""},
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+// OtherGroup is the name of the bucket for repositories without a group key
+// or, with an explicit group list, whose key is not listed.
+const OtherGroup = "Other"
+
+// Group orders.
+const (
+	GroupOrderCount = "count"
+	GroupOrderAlpha = "alpha"
+)
+
+// GroupOptions controls how repositories are grouped.
+type GroupOptions struct {
+	// By is the grouping key: "language", "topic", "owner" or "year".
+	By string
+	// Top limits the number of repositories per group; 0 means no limit.
+	Top int
+	// Order is GroupOrderCount (largest first, the default) or GroupOrderAlpha.
+	// It is ignored when Explicit is set.
+	Order string
+	// Explicit lists the groups to show, in order. Other groups are merged
+	// into OtherGroup. Matching is case-insensitive.
+	Explicit []string
+}
+
+// RepoGroup is a named group of repositories.
+type RepoGroup struct {
+	Name  string
+	Repos []githubapi.Repository
+}
+
+// GroupRepos groups repos by the requested key, keeping their relative order
+// within each group. With "topic", a repository appears in every group for its
+// topics. OtherGroup, when present, is always last.
+func GroupRepos(repos []githubapi.Repository, opts GroupOptions) []RepoGroup {
+	explicit := make(map[string]int, len(opts.Explicit))
+	for i, name := range opts.Explicit {
+		explicit[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	var groups []*RepoGroup
+	byKey := make(map[string]*RepoGroup)
+	var other RepoGroup
+
+	for _, r := range repos {
+		keys := groupKeys(r, opts.By)
+		if len(keys) == 0 {
+			other.Repos = append(other.Repos, r)
+			continue
+		}
+		addedOther := false
+		for _, key := range keys {
+			norm := strings.ToLower(key)
+			if len(explicit) > 0 {
+				if _, ok := explicit[norm]; !ok {
+					if !addedOther {
+						other.Repos = append(other.Repos, r)
+						addedOther = true
+					}
+					continue
+				}
+			}
+			g, ok := byKey[norm]
+			if !ok {
+				g = &RepoGroup{Name: key}
+				byKey[norm] = g
+				groups = append(groups, g)
+			}
+			g.Repos = append(g.Repos, r)
+		}
+	}
+
+	switch {
+	case len(explicit) > 0:
+		sort.SliceStable(groups, func(i, j int) bool {
+			return explicit[strings.ToLower(groups[i].Name)] < explicit[strings.ToLower(groups[j].Name)]
+		})
+	case opts.Order == GroupOrderAlpha:
+		sort.SliceStable(groups, func(i, j int) bool {
+			return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+		})
+	default:
+		sort.SliceStable(groups, func(i, j int) bool {
+			if len(groups[i].Repos) != len(groups[j].Repos) {
+				return len(groups[i].Repos) > len(groups[j].Repos)
+			}
+			return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+		})
+	}
+
+	result := make([]RepoGroup, 0, len(groups)+1)
+	for _, g := range groups {
+		result = append(result, RepoGroup{Name: g.Name, Repos: TopN(g.Repos, opts.Top)})
+	}
+	if len(other.Repos) > 0 {
+		result = append(result, RepoGroup{Name: OtherGroup, Repos: TopN(other.Repos, opts.Top)})
+	}
+	return result
+}
+
+// groupKeys returns the group names a repository belongs to.
+func groupKeys(r githubapi.Repository, by string) []string {
+	switch by {
+	case "language":
+		if lang := strings.TrimSpace(r.Language); lang != "" {
+			return []string{lang}
+		}
+	case "topic":
+		var keys []string
+		seen := make(map[string]bool)
+		for _, t := range r.Topics {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t != "" && !seen[t] {
+				seen[t] = true
+				keys = append(keys, t)
+			}
+		}
+		return keys
+	case "owner":
+		if owner, _, ok := strings.Cut(r.FullName, "/"); ok && owner != "" {
+			return []string{owner}
+		}
+	case "year":
+		if !r.PushedAt.IsZero() {
+			return []string{strconv.Itoa(r.PushedAt.Year())}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+func groupNames(groups []RepoGroup) []string {
+	names := make([]string, len(groups))
+	for i, g := range groups {
+		names[i] = g.Name
+	}
+	return names
+}
+
+func TestGroupReposByLanguage(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "a", Language: "Go"},
+		{Name: "b", Language: "Rust"},
+		{Name: "c", Language: "Go"},
+		{Name: "d"},
+	}
+
+	groups := GroupRepos(repos, GroupOptions{By: "language"})
+
+	if got := strings.Join(groupNames(groups), ","); got != "Go,Rust,Other" {
+		t.Fatalf("groups = %s, want Go,Rust,Other", got)
+	}
+	if len(groups[0].Repos) != 2 || groups[0].Repos[0].Name != "a" || groups[0].Repos[1].Name != "c" {
+		t.Errorf("Go group should keep input order: %+v", groups[0].Repos)
+	}
+	if groups[2].Repos[0].Name != "d" {
+		t.Errorf("repo without language should be in Other: %+v", groups[2].Repos)
+	}
+}
+
+func TestGroupReposByTopicMultipleGroups(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "a", Topics: []string{"cli", "go"}},
+		{Name: "b", Topics: []string{"GO"}},
+	}
+
+	groups := GroupRepos(repos, GroupOptions{By: "topic"})
+
+	if got := strings.Join(groupNames(groups), ","); got != "go,cli" {
+		t.Fatalf("groups = %s, want go,cli", got)
+	}
+	if len(groups[0].Repos) != 2 || len(groups[1].Repos) != 1 {
+		t.Errorf("repo should appear in every topic group: %+v", groups)
+	}
+}
+
+func TestGroupReposByOwnerAndYear(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "a", FullName: "alice/a", PushedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "b", FullName: "acme/b", PushedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	if got := strings.Join(groupNames(GroupRepos(repos, GroupOptions{By: "owner", Order: GroupOrderAlpha})), ","); got != "acme,alice" {
+		t.Errorf("owner groups = %s, want acme,alice", got)
+	}
+	if got := strings.Join(groupNames(GroupRepos(repos, GroupOptions{By: "year", Order: GroupOrderAlpha})), ","); got != "2024,2025" {
+		t.Errorf("year groups = %s, want 2024,2025", got)
+	}
+}
+
+func TestGroupReposExplicitList(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "a", Language: "Go"},
+		{Name: "b", Language: "Rust"},
+		{Name: "c", Language: "Python"},
+		{Name: "d", Language: "Go"},
+	}
+
+	groups := GroupRepos(repos, GroupOptions{By: "language", Explicit: []string{"rust", "Go"}})
+
+	if got := strings.Join(groupNames(groups), ","); got != "Rust,Go,Other" {
+		t.Fatalf("groups = %s, want Rust,Go,Other", got)
+	}
+	if groups[2].Repos[0].Name != "c" {
+		t.Errorf("unlisted language should be in Other: %+v", groups[2].Repos)
+	}
+}
+
+func TestGroupReposTopPerGroup(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "a", Language: "Go"},
+		{Name: "b", Language: "Go"},
+		{Name: "c", Language: "Go"},
+		{Name: "d", Language: "Rust"},
+	}
+
+	groups := GroupRepos(repos, GroupOptions{By: "language", Top: 2})
+
+	if len(groups[0].Repos) != 2 || groups[0].Repos[1].Name != "b" {
+		t.Errorf("Go group should be truncated to 2: %+v", groups[0].Repos)
+	}
+	if len(groups[1].Repos) != 1 {
+		t.Errorf("Rust group should be unchanged: %+v", groups[1].Repos)
+	}
+}
+
+func TestRenderMarkdownGroups(t *testing.T) {
+	groups := []RepoGroup{
+		{Name: "Go", Repos: []githubapi.Repository{{Name: "a", HTMLURL: "https://github.com/u/a"}}},
+		{Name: "Other", Repos: []githubapi.Repository{{Name: "b", HTMLURL: "https://github.com/u/b"}}},
+	}
+
+	result := RenderMarkdownGroups(groups, "CURRENT PROJECTS", MarkdownOptions{})
+
+	expected := "<!-- BEGIN CURRENT PROJECTS -->\n" +
+		"## Current Projects\n\n" +
+		"### Go\n\n" +
+		"- [a](https://github.com/u/a)\n" +
+		"\n" +
+		"### Other\n\n" +
+		"- [b](https://github.com/u/b)\n" +
+		"<!-- END CURRENT PROJECTS -->\n"
+	if result != expected {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", result, expected)
+	}
+}
+
+func TestRenderMarkdownGroupsEmpty(t *testing.T) {
+	result := RenderMarkdownGroups(nil, "M", MarkdownOptions{})
+	if !strings.Contains(result, "_No public projects matched._") {
+		t.Errorf("missing no-results message: %s", result)
+	}
+}
+
+func TestRenderJSONGroups(t *testing.T) {
+	groups := []RepoGroup{
+		{Name: "Go", Repos: []githubapi.Repository{{Name: "a", HTMLURL: "https://github.com/u/a"}}},
+	}
+
+	result, err := RenderJSONGroups(groups)
+	if err != nil {
+		t.Fatalf("RenderJSONGroups: %v", err)
+	}
+
+	var decoded []JSONGroup
+	if err := json.Unmarshal([]byte(result), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, result)
+	}
+	if len(decoded) != 1 || decoded[0].Group != "Go" || len(decoded[0].Repos) != 1 || decoded[0].Repos[0].Name != "a" {
+		t.Errorf("unexpected groups: %+v", decoded)
+	}
+}
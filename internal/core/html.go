@@ -0,0 +1,204 @@
+package core
+
+import (
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+// HTML layouts.
+const (
+	HTMLLayoutList  = "list"
+	HTMLLayoutTable = "table"
+)
+
+// HTMLOptions controls HTML rendering.
+type HTMLOptions struct {
+	MarkdownOptions
+	// Layout is HTMLLayoutList (the default) or HTMLLayoutTable.
+	Layout string
+	// Columns selects and orders table columns. Empty means TableColumns.
+	Columns []string
+	// Standalone wraps the fragment in a complete HTML document.
+	Standalone bool
+}
+
+type htmlLink struct {
+	Text string
+	URL  string
+	// Note is plain text shown after the link, e.g. "(private)".
+	Note string
+}
+
+type htmlRepo struct {
+	Name        htmlLink
+	Language    string
+	Description string
+	Cells       []htmlLink
+}
+
+type htmlGroup struct {
+	Name  string
+	Repos []htmlRepo
+}
+
+type htmlData struct {
+	Heading string
+	Table   bool
+	Titles  []string
+	Groups  []htmlGroup
+}
+
+// htmlTemplate renders the section body. Markers are written outside the
+// template because html/template strips comments.
+var htmlTemplate = template.Must(template.New("html").Parse(`{{define "link"}}{{if .URL}}<a href="{{.URL}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}{{with .Note}} {{.}}{{end}}{{end -}}
+<section class="current-projects">
+<h2>{{.Heading}}</h2>
+{{- if not .Groups}}
+<p><em>No public projects matched.</em></p>
+{{- end}}
+{{- range .Groups}}
+{{- if .Name}}
+<h3>{{.Name}}</h3>
+{{- end}}
+{{- if $.Table}}
+<table>
+<thead>
+<tr>{{range $.Titles}}<th>{{.}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{- range .Repos}}
+<tr>{{range .Cells}}<td>{{template "link" .}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<ul>
+{{- range .Repos}}
+<li>{{template "link" .Name}}{{with .Language}} ({{.}}){{end}}{{with .Description}} - {{.}}{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+</section>
+`))
+
+var htmlPageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.}}</title>
+</head>
+<body>
+`))
+
+// RenderHTML produces an HTML fragment for the given repos, wrapped in the
+// same comment markers as the Markdown output so it can be patched into an
+// HTML file with PatchREADME.
+func RenderHTML(repos []githubapi.Repository, marker string, opts HTMLOptions) (string, error) {
+	var groups []RepoGroup
+	pinned := opts.PinnedCount
+	if pinned > len(repos) {
+		pinned = len(repos)
+	}
+	switch {
+	case len(repos) == 0:
+	case pinned > 0:
+		groups = append(groups, RepoGroup{Name: "Pinned", Repos: repos[:pinned]})
+		if pinned < len(repos) {
+			groups = append(groups, RepoGroup{Name: "More Projects", Repos: repos[pinned:]})
+		}
+	default:
+		groups = []RepoGroup{{Repos: repos}}
+	}
+	return RenderHTMLGroups(groups, marker, opts)
+}
+
+// RenderHTMLGroups produces an HTML fragment with a sub-heading per named group.
+func RenderHTMLGroups(groups []RepoGroup, marker string, opts HTMLOptions) (string, error) {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = TableColumns
+	}
+
+	heading := normalizeInlineText(opts.Heading)
+	if heading == "" {
+		heading = "Current Projects"
+	}
+
+	data := htmlData{Heading: heading, Table: opts.Layout == HTMLLayoutTable}
+	for _, c := range columns {
+		data.Titles = append(data.Titles, tableColumnTitles[c])
+	}
+	for _, g := range groups {
+		hg := htmlGroup{Name: normalizeInlineText(g.Name)}
+		for _, r := range g.Repos {
+			hr := htmlRepo{
+				Name:        htmlNameLink(r, opts.MarkdownOptions),
+				Language:    strings.TrimSpace(r.Language),
+				Description: normalizeInlineText(r.Description),
+			}
+			if data.Table {
+				for _, c := range columns {
+					hr.Cells = append(hr.Cells, htmlCell(r, c, opts.MarkdownOptions))
+				}
+			}
+			hg.Repos = append(hg.Repos, hr)
+		}
+		data.Groups = append(data.Groups, hg)
+	}
+
+	var body strings.Builder
+	if err := htmlTemplate.Execute(&body, data); err != nil {
+		return "", fmt.Errorf("rendering HTML: %w", err)
+	}
+
+	var sb strings.Builder
+	if opts.Standalone {
+		if err := htmlPageTemplate.Execute(&sb, heading); err != nil {
+			return "", fmt.Errorf("rendering HTML: %w", err)
+		}
+	}
+	sb.WriteString(fmt.Sprintf("<!-- BEGIN %s -->\n", marker))
+	sb.WriteString(body.String())
+	sb.WriteString(fmt.Sprintf("<!-- END %s -->\n", marker))
+	if opts.Standalone {
+		sb.WriteString("</body>\n</html>\n")
+	}
+	return sb.String(), nil
+}
+
+// htmlNameLink returns the repository name, linked unless the URL is unsafe
+// or the repository is private and opts hides private links. Private
+// repositories are labeled "(private)".
+func htmlNameLink(r githubapi.Repository, opts MarkdownOptions) htmlLink {
+	link := htmlLink{Text: strings.TrimSpace(r.Name), URL: repoLink(r, opts.PrivateStyle)}
+	if r.Private {
+		link.Note = "(private)"
+	}
+	return link
+}
+
+func htmlCell(r githubapi.Repository, column string, opts MarkdownOptions) htmlLink {
+	switch column {
+	case "name":
+		return htmlNameLink(r, opts)
+	case "description":
+		return htmlLink{Text: normalizeInlineText(r.Description)}
+	case "language":
+		return htmlLink{Text: strings.TrimSpace(r.Language)}
+	case "stars":
+		return htmlLink{Text: strconv.Itoa(r.StargazersCount)}
+	case "pushed":
+		if r.PushedAt.IsZero() {
+			return htmlLink{}
+		}
+		return htmlLink{Text: r.PushedAt.UTC().Format("2006-01-02")}
+	default:
+		return htmlLink{}
+	}
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+func TestRenderHTMLList(t *testing.T) {
+	repos := []githubapi.Repository{
+		{
+			Name:        "awesome",
+			HTMLURL:     "https://github.com/u/awesome",
+			Description: "An awesome project",
+			Language:    "Go",
+		},
+	}
+
+	result, err := RenderHTML(repos, "CURRENT PROJECTS", HTMLOptions{})
+	if err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+
+	expected := "<!-- BEGIN CURRENT PROJECTS -->\n" +
+		"<section class=\"current-projects\">\n" +
+		"<h2>Current Projects</h2>\n" +
+		"<ul>\n" +
+		"<li><a href=\"https://github.com/u/awesome\">awesome</a> (Go) - An awesome project</li>\n" +
+		"</ul>\n" +
+		"</section>\n" +
+		"<!-- END CURRENT PROJECTS -->\n"
+	if result != expected {
+		t.Errorf("unexpected HTML:\n%s\nwant:\n%s", result, expected)
+	}
+}
+
+func TestRenderHTMLEscapes(t *testing.T) {
+	repos := []githubapi.Repository{
+		{
+			Name:        "<b>x</b>",
+			HTMLURL:     "javascript:alert(1)",
+			Description: `"quoted" & <script>alert(1)</script>`,
+		},
+	}
+
+	result, err := RenderHTML(repos, "M", HTMLOptions{MarkdownOptions: MarkdownOptions{Heading: "<i>Mine</i>"}})
+	if err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+
+	for _, bad := range []string{"<script>", "<b>", "<i>", "javascript:", "href"} {
+		if strings.Contains(result, bad) {
+			t.Errorf("output should not contain %q:\n%s", bad, result)
+		}
+	}
+	if !strings.Contains(result, "&lt;script&gt;") || !strings.Contains(result, "&lt;i&gt;Mine&lt;/i&gt;") {
+		t.Errorf("expected escaped text:\n%s", result)
+	}
+}
+
+func TestRenderHTMLTable(t *testing.T) {
+	repos := []githubapi.Repository{
+		{
+			Name:            "secret",
+			HTMLURL:         "https://github.com/u/secret",
+			Private:         true,
+			StargazersCount: 5,
+			PushedAt:        time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	result, err := RenderHTML(repos, "M", HTMLOptions{Layout: HTMLLayoutTable, Columns: []string{"name", "stars", "pushed"}})
+	if err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+
+	if !strings.Contains(result, "<tr><th>Name</th><th>Stars</th><th>Last Push</th></tr>") {
+		t.Errorf("unexpected table header:\n%s", result)
+	}
+	if !strings.Contains(result, "<tr><td>secret (private)</td><td>5</td><td>2025-01-15</td></tr>") {
+		t.Errorf("unexpected table row:\n%s", result)
+	}
+}
+
+func TestRenderHTMLEmpty(t *testing.T) {
+	result, err := RenderHTML(nil, "M", HTMLOptions{})
+	if err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+	if !strings.Contains(result, "<p><em>No public projects matched.</em></p>") || strings.Contains(result, "<ul>") {
+		t.Errorf("unexpected empty output:\n%s", result)
+	}
+}
+
+func TestRenderHTMLGroupsAndPinned(t *testing.T) {
+	repos := []githubapi.Repository{{Name: "a"}, {Name: "b"}}
+
+	result, err := RenderHTML(repos, "M", HTMLOptions{MarkdownOptions: MarkdownOptions{PinnedCount: 1}})
+	if err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+	if !strings.Contains(result, "<h3>Pinned</h3>\n<ul>\n<li>a</li>\n</ul>\n<h3>More Projects</h3>\n<ul>\n<li>b</li>\n</ul>\n") {
+		t.Errorf("unexpected pinned output:\n%s", result)
+	}
+
+	result, err = RenderHTMLGroups([]RepoGroup{{Name: "Go", Repos: repos}}, "M", HTMLOptions{})
+	if err != nil {
+		t.Fatalf("RenderHTMLGroups: %v", err)
+	}
+	if !strings.Contains(result, "<h3>Go</h3>\n<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n") {
+		t.Errorf("unexpected grouped output:\n%s", result)
+	}
+}
+
+func TestRenderHTMLStandalone(t *testing.T) {
+	result, err := RenderHTML(nil, "M", HTMLOptions{Standalone: true})
+	if err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+	if !strings.HasPrefix(result, "<!DOCTYPE html>\n") || !strings.HasSuffix(result, "</body>\n</html>\n") {
+		t.Errorf("expected a complete document:\n%s", result)
+	}
+	if !strings.Contains(result, "<title>Current Projects</title>") || !strings.Contains(result, "<!-- BEGIN M -->") {
+		t.Errorf("missing title or markers:\n%s", result)
+	}
+}
+
+func TestRenderHTMLPatchable(t *testing.T) {
+	existing := "<html><body>\n<!-- BEGIN CURRENT PROJECTS -->\nold\n<!-- END CURRENT PROJECTS -->\n</body></html>\n"
+	section, err := RenderHTML([]githubapi.Repository{{Name: "n"}}, "CURRENT PROJECTS", HTMLOptions{})
+	if err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+
+	result, err := PatchREADME(existing, section, "CURRENT PROJECTS", false)
+	if err != nil {
+		t.Fatalf("PatchREADME: %v", err)
+	}
+	if strings.Contains(result.Content, "old") || !strings.Contains(result.Content, "<li>n</li>") || !strings.HasSuffix(result.Content, "</body></html>\n") {
+		t.Errorf("unexpected patched content: %s", result.Content)
+	}
+}
@@ -0,0 +1,229 @@
+package core
+
+import "strings"
+
+// markdownLine is a line of a Markdown document that is not part of a code
+// block.
+type markdownLine struct {
+	// Number is the 1-based line number.
+	Number int
+	// Offset is the byte offset of the line in the document.
+	Offset int
+	// Text is the line without its line terminator.
+	Text string
+}
+
+// proseLines returns the lines of content outside fenced code blocks
+// (``` or ~~~), indented code blocks and HTML <pre> blocks, following the
+// CommonMark block rules closely enough to skip documented examples.
+// Indented list continuations are treated as code, which errs on the side
+// of not matching.
+func proseLines(content string) []markdownLine {
+	var (
+		lines     []markdownLine
+		fence     string // opening fence while inside a fenced block
+		inPre     bool
+		inIndent  bool
+		codeStart = true // an indented line here would open a code block
+	)
+
+	for _, line := range documentLines(content) {
+		blank := strings.TrimSpace(line.Text) == ""
+
+		// Indented code cannot interrupt a paragraph, so it only starts
+		// after a blank line, an ATX heading or the end of another block.
+		nextCodeStart := blank
+		switch {
+		case fence != "":
+			if isClosingFence(line.Text, fence) {
+				fence = ""
+				nextCodeStart = true
+			}
+		case inPre:
+			if strings.Contains(strings.ToLower(line.Text), "</pre>") {
+				inPre = false
+				nextCodeStart = true
+			}
+		case blank:
+			// Blank lines neither start nor end an indented block.
+		case indentWidth(line.Text) >= 4 && (codeStart || inIndent):
+			inIndent = true
+		default:
+			inIndent = false
+			if f := openingFence(line.Text); f != "" {
+				fence = f
+			} else if isPreStart(line.Text) {
+				inPre = !strings.Contains(strings.ToLower(line.Text), "</pre>")
+				nextCodeStart = !inPre
+			} else {
+				lines = append(lines, line)
+				nextCodeStart = isATXHeading(line.Text)
+			}
+		}
+		codeStart = nextCodeStart
+	}
+	return lines
+}
+
+// documentLines returns every line of content with its number and offset.
+func documentLines(content string) []markdownLine {
+	var lines []markdownLine
+	offset := 0
+	for i, raw := range strings.SplitAfter(content, "\n") {
+		if raw == "" {
+			break
+		}
+		lines = append(lines, markdownLine{Number: i + 1, Offset: offset, Text: strings.TrimRight(raw, "\r\n")})
+		offset += len(raw)
+	}
+	return lines
+}
+
+// indentWidth returns the leading indentation of s in columns, counting a
+// tab as advancing to the next multiple of four.
+func indentWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// isATXHeading reports whether s is an ATX heading such as "## Title".
+func isATXHeading(s string) bool {
+	if indentWidth(s) > 3 {
+		return false
+	}
+	s = strings.TrimLeft(s, " \t")
+	n := 0
+	for n < len(s) && s[n] == '#' {
+		n++
+	}
+	return n >= 1 && n <= 6 && (n == len(s) || s[n] == ' ' || s[n] == '\t')
+}
+
+// openingFence returns the fence (e.g. "```" or "~~~~") that s opens, or ""
+// if s does not open a fenced code block.
+func openingFence(s string) string {
+	if indentWidth(s) > 3 {
+		return ""
+	}
+	s = strings.TrimLeft(s, " \t")
+	if s == "" || (s[0] != '`' && s[0] != '~') {
+		return ""
+	}
+	n := 0
+	for n < len(s) && s[n] == s[0] {
+		n++
+	}
+	if n < 3 {
+		return ""
+	}
+	// A backtick fence's info string may not contain backticks.
+	if s[0] == '`' && strings.Contains(s[n:], "`") {
+		return ""
+	}
+	return s[:n]
+}
+
+// isClosingFence reports whether s closes the block opened by fence.
+func isClosingFence(s, fence string) bool {
+	if indentWidth(s) > 3 {
+		return false
+	}
+	s = strings.TrimSpace(s)
+	return len(s) >= len(fence) && strings.Trim(s, fence[:1]) == ""
+}
+
+// isPreStart reports whether s starts an HTML <pre> block.
+func isPreStart(s string) bool {
+	if indentWidth(s) > 3 {
+		return false
+	}
+	s = strings.ToLower(strings.TrimLeft(s, " \t"))
+	if !strings.HasPrefix(s, "<pre") {
+		return false
+	}
+	rest := s[len("<pre"):]
+	return rest == "" || rest[0] == '>' || rest[0] == ' ' || rest[0] == '\t'
+}
+
+// inlineCodeSpans returns the [start, end) byte ranges of the inline code
+// spans in s. A span opened by a backtick run ends at the next run of the
+// same length.
+func inlineCodeSpans(s string) [][2]int {
+	var spans [][2]int
+	for i := 0; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(s) && s[i] == '`' {
+			i++
+		}
+		run := i - start
+		for j := i; j < len(s); {
+			if s[j] != '`' {
+				j++
+				continue
+			}
+			k := j
+			for k < len(s) && s[k] == '`' {
+				k++
+			}
+			if k-j == run {
+				spans = append(spans, [2]int{start, k})
+				i = k
+				break
+			}
+			j = k
+		}
+	}
+	return spans
+}
+
+// markerOffsets returns the byte offsets of the occurrences of marker in
+// text that are outside inline code spans.
+func markerOffsets(text, marker string) []int {
+	return offsetsOutside(text, marker, inlineCodeSpans(text))
+}
+
+// htmlMarkerOffsets returns the byte offsets of every occurrence of marker
+// in text. HTML has no inline code syntax that hides a comment.
+func htmlMarkerOffsets(text, marker string) []int {
+	return offsetsOutside(text, marker, nil)
+}
+
+// offsetsOutside returns the byte offsets of the occurrences of marker in
+// text that are outside spans.
+func offsetsOutside(text, marker string, spans [][2]int) []int {
+	var offsets []int
+	for from := 0; ; {
+		i := strings.Index(text[from:], marker)
+		if i < 0 {
+			return offsets
+		}
+		i += from
+		if !inSpans(spans, i) {
+			offsets = append(offsets, i)
+		}
+		from = i + len(marker)
+	}
+}
+
+func inSpans(spans [][2]int, i int) bool {
+	for _, s := range spans {
+		if i >= s[0] && i < s[1] {
+			return true
+		}
+	}
+	return false
+}
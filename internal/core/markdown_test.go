@@ -0,0 +1,97 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func proseTexts(content string) []string {
+	var texts []string
+	for _, l := range proseLines(content) {
+		texts = append(texts, l.Text)
+	}
+	return texts
+}
+
+func TestProseLinesSkipsFencedBlocks(t *testing.T) {
+	content := "a\n```go\nx\n```\nb\n~~~~\n~~~\ny\n~~~~\nc\n"
+	want := []string{"a", "b", "c"}
+	if got := proseTexts(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("proseLines = %q, want %q", got, want)
+	}
+}
+
+func TestProseLinesFenceNeedsMatchingCloser(t *testing.T) {
+	// A shorter or different fence does not close the block.
+	content := "````\n```\n~~~~\nx\n````\nafter\n"
+	want := []string{"after"}
+	if got := proseTexts(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("proseLines = %q, want %q", got, want)
+	}
+}
+
+func TestProseLinesSkipsIndentedCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "after blank line",
+			content: "para\n    not code\n\n    code\n\n\tmore code\nend\n",
+			want:    []string{"para", "    not code", "end"},
+		},
+		{
+			name:    "after ATX heading",
+			content: "# Heading\n    <!-- code -->\n##\n    code\n#hashtag\n    not code\n",
+			want:    []string{"# Heading", "##", "#hashtag", "    not code"},
+		},
+		{
+			name:    "after closing fence",
+			content: "```\nx\n```\n    code\nend\n",
+			want:    []string{"end"},
+		},
+		{
+			name:    "after closing pre",
+			content: "<pre>\nx\n</pre>\n    code\n<pre>y</pre>\n    code\nend\n",
+			want:    []string{"end"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := proseTexts(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("proseLines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProseLinesSkipsPre(t *testing.T) {
+	content := "a\n<PRE class=\"x\">\nx\n</pre>\nb\n<pre>one line</pre>\nc\n<preview>\n"
+	want := []string{"a", "b", "c", "<preview>"}
+	if got := proseTexts(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("proseLines = %q, want %q", got, want)
+	}
+}
+
+func TestProseLinesNumbersAndOffsets(t *testing.T) {
+	content := "a\r\n```\r\nx\r\n```\r\nb"
+	lines := proseLines(content)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if lines[1].Number != 5 || lines[1].Offset != 16 || lines[1].Text != "b" {
+		t.Errorf("last line = %+v, want {5 16 b}", lines[1])
+	}
+}
+
+func TestMarkerOffsetsSkipsInlineCode(t *testing.T) {
+	text := "Use `<!-- M -->` or ``<!-- M -->``, then <!-- M --> and <!-- M -->"
+	want := []int{41, 56}
+	if got := markerOffsets(text, "<!-- M -->"); !reflect.DeepEqual(got, want) {
+		t.Errorf("markerOffsets = %v, want %v", got, want)
+	}
+	if got := markerOffsets("`<!-- M -->`", "<!-- M -->"); got != nil {
+		t.Errorf("markerOffsets = %v, want nil", got)
+	}
+}
@@ -0,0 +1,229 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Marker policies decide how PatchREADMEWithOptions handles a README that
+// contains more than one block for the same marker.
+const (
+	// MarkerPolicyError rejects the README (the default).
+	MarkerPolicyError = "error"
+	// MarkerPolicyFirst updates the first block and leaves the others alone.
+	MarkerPolicyFirst = "first"
+	// MarkerPolicyAll updates every block.
+	MarkerPolicyAll = "all"
+)
+
+// IsMarkerPolicy reports whether name is a supported marker policy.
+func IsMarkerPolicy(name string) bool {
+	switch name {
+	case MarkerPolicyError, MarkerPolicyFirst, MarkerPolicyAll:
+		return true
+	}
+	return false
+}
+
+// MarkerBlock is a matched BEGIN/END marker pair.
+type MarkerBlock struct {
+	// BeginLine and EndLine are the 1-based lines of the markers.
+	BeginLine int
+	EndLine   int
+	// Attrs holds the key=value attributes of the BEGIN marker, as in
+	// <!-- BEGIN CURRENT PROJECTS top=5 sort=stars -->. It is nil when the
+	// marker has none.
+	Attrs map[string]string
+
+	attrText string // attributes as written, kept when the block is rewritten
+	start    int    // offset of the BEGIN marker
+	end      int    // offset just past the END marker and its line terminator
+}
+
+// MarkerIssue is a malformed marker found by ScanMarkers.
+type MarkerIssue struct {
+	Line    int
+	Message string
+}
+
+func (i MarkerIssue) String() string {
+	return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+}
+
+// MarkerScan is the result of ScanMarkers.
+type MarkerScan struct {
+	Blocks []MarkerBlock
+	Issues []MarkerIssue
+}
+
+// ScanMarkers finds every BEGIN/END pair for marker in content, skipping
+// code blocks and inline code like PatchREADME. END markers without a BEGIN,
+// BEGIN markers nested inside an open block, BEGIN markers that are never
+// closed and malformed attributes are reported as issues, sorted by line.
+//
+// A BEGIN marker may carry attributes after the marker name, written as
+// key=value or key="value with spaces". Text after the name that is not an
+// attribute means the comment belongs to a different marker.
+func ScanMarkers(content, marker string) MarkerScan {
+	return scanMarkers(content, marker, false)
+}
+
+// scanMarkers is ScanMarkers for a Markdown document, or for an HTML
+// document when html is true. HTML has no Markdown code blocks, and a
+// comment inside <pre> is still a comment, so every line is scanned.
+func scanMarkers(content, marker string, html bool) MarkerScan {
+	beginPrefix := fmt.Sprintf("<!-- BEGIN %s", marker)
+	endMarker := fmt.Sprintf("<!-- END %s -->", marker)
+
+	type occurrence struct {
+		line   markdownLine
+		offset int // offset of the marker within the line
+		begin  bool
+		attrs  beginAttrs
+	}
+
+	var (
+		scan       MarkerScan
+		open       *occurrence
+		orphanEnds []int
+		beginLines []int
+	)
+	lines, offsets := proseLines(content), markerOffsets
+	if html {
+		lines, offsets = documentLines(content), htmlMarkerOffsets
+	}
+	for _, line := range lines {
+		var found []occurrence
+		for _, off := range offsets(line.Text, beginPrefix) {
+			attrs, ok, err := parseBeginAttrs(line.Text[off+len(beginPrefix):])
+			if !ok {
+				continue
+			}
+			if err != nil {
+				scan.Issues = append(scan.Issues, MarkerIssue{Line: line.Number, Message: err.Error()})
+			}
+			found = append(found, occurrence{line: line, offset: off, begin: true, attrs: attrs})
+		}
+		for _, off := range offsets(line.Text, endMarker) {
+			found = append(found, occurrence{line: line, offset: off})
+		}
+		sort.Slice(found, func(i, j int) bool { return found[i].offset < found[j].offset })
+
+		for _, occ := range found {
+			switch {
+			case occ.begin && open != nil:
+				scan.Issues = append(scan.Issues, MarkerIssue{
+					Line:    occ.line.Number,
+					Message: fmt.Sprintf("BEGIN marker is nested inside the block opened at line %d", open.line.Number),
+				})
+			case occ.begin:
+				o := occ
+				open = &o
+				beginLines = append(beginLines, occ.line.Number)
+			case open == nil:
+				orphanEnds = append(orphanEnds, occ.line.Number)
+			default:
+				scan.Blocks = append(scan.Blocks, MarkerBlock{
+					BeginLine: open.line.Number,
+					EndLine:   occ.line.Number,
+					Attrs:     open.attrs.values,
+					attrText:  open.attrs.text,
+					start:     open.line.Offset + open.offset,
+					end:       endOfMarkerLine(content, occ.line.Offset+occ.offset+len(endMarker)),
+				})
+				open = nil
+			}
+		}
+	}
+
+	if open != nil {
+		scan.Issues = append(scan.Issues, MarkerIssue{Line: open.line.Number, Message: "BEGIN marker is never closed"})
+	}
+	for _, line := range orphanEnds {
+		issue := MarkerIssue{Line: line, Message: "END marker has no matching BEGIN marker"}
+		for _, b := range beginLines {
+			if b > line {
+				issue.Message = fmt.Sprintf("END marker appears before BEGIN marker at line %d", b)
+				break
+			}
+		}
+		scan.Issues = append(scan.Issues, issue)
+	}
+	sort.SliceStable(scan.Issues, func(i, j int) bool { return scan.Issues[i].Line < scan.Issues[j].Line })
+	return scan
+}
+
+type beginAttrs struct {
+	text   string
+	values map[string]string
+}
+
+// parseBeginAttrs parses the rest of a BEGIN marker after the marker name,
+// up to and including "-->". ok is false if rest does not complete a BEGIN
+// marker for this name; err reports malformed or repeated attributes.
+func parseBeginAttrs(rest string) (attrs beginAttrs, ok bool, err error) {
+	end := strings.Index(rest, "-->")
+	if end < 0 {
+		return beginAttrs{}, false, nil
+	}
+	body := rest[:end]
+	if body != "" && body[0] != ' ' && body[0] != '\t' {
+		return beginAttrs{}, false, nil
+	}
+	attrs.text = strings.TrimSpace(body)
+
+	for s := attrs.text; s != ""; s = strings.TrimLeft(s, " \t") {
+		eq := strings.IndexAny(s, "= \t")
+		if eq <= 0 || s[eq] != '=' {
+			return beginAttrs{}, false, nil
+		}
+		key := s[:eq]
+		s = s[eq+1:]
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			closing := strings.IndexByte(s[1:], '"')
+			if closing < 0 {
+				return attrs, true, fmt.Errorf("BEGIN marker attribute %q has an unterminated quote", key)
+			}
+			value, s = s[1:closing+1], s[closing+2:]
+			if s != "" && s[0] != ' ' && s[0] != '\t' {
+				return attrs, true, fmt.Errorf("BEGIN marker attribute %q has text after its closing quote", key)
+			}
+		} else if i := strings.IndexAny(s, " \t"); i >= 0 {
+			value, s = s[:i], s[i:]
+		} else {
+			value, s = s, ""
+		}
+
+		if _, dup := attrs.values[key]; dup {
+			return attrs, true, fmt.Errorf("BEGIN marker attribute %q is repeated", key)
+		}
+		if attrs.values == nil {
+			attrs.values = make(map[string]string)
+		}
+		attrs.values[key] = value
+	}
+	return attrs, true, nil
+}
+
+// endOfMarkerLine returns i advanced past a line terminator at content[i:].
+func endOfMarkerLine(content string, i int) int {
+	if i < len(content) && content[i] == '\r' {
+		i++
+	}
+	if i < len(content) && content[i] == '\n' {
+		i++
+	}
+	return i
+}
+
+// blockLines formats the BEGIN lines of blocks as "5, 20".
+func blockLines(blocks []MarkerBlock) string {
+	lines := make([]string, len(blocks))
+	for i, b := range blocks {
+		lines[i] = fmt.Sprint(b.BeginLine)
+	}
+	return strings.Join(lines, ", ")
+}
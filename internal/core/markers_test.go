@@ -0,0 +1,140 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanMarkersBlocks(t *testing.T) {
+	content := "# Profile\n" +
+		"<!-- BEGIN X -->\na\n<!-- END X -->\n" +
+		"```\n<!-- BEGIN X -->\n```\n" +
+		"<!-- BEGIN X --><!-- END X -->\n"
+
+	scan := ScanMarkers(content, "X")
+	if len(scan.Issues) != 0 {
+		t.Fatalf("unexpected issues: %v", scan.Issues)
+	}
+	if len(scan.Blocks) != 2 {
+		t.Fatalf("got %d blocks, want 2", len(scan.Blocks))
+	}
+	if b := scan.Blocks[0]; b.BeginLine != 2 || b.EndLine != 4 {
+		t.Errorf("block 0 lines = %d-%d, want 2-4", b.BeginLine, b.EndLine)
+	}
+	if b := scan.Blocks[1]; b.BeginLine != 8 || b.EndLine != 8 {
+		t.Errorf("block 1 lines = %d-%d, want 8-8", b.BeginLine, b.EndLine)
+	}
+}
+
+func TestScanMarkersIssues(t *testing.T) {
+	content := "<!-- END X -->\n" +
+		"<!-- BEGIN X -->\n" +
+		"<!-- BEGIN X -->\n" +
+		"<!-- END X -->\n" +
+		"<!-- END X -->\n" +
+		"<!-- BEGIN X -->\n"
+
+	scan := ScanMarkers(content, "X")
+	want := []MarkerIssue{
+		{Line: 1, Message: "END marker appears before BEGIN marker at line 2"},
+		{Line: 3, Message: "BEGIN marker is nested inside the block opened at line 2"},
+		{Line: 5, Message: "END marker appears before BEGIN marker at line 6"},
+		{Line: 6, Message: "BEGIN marker is never closed"},
+	}
+	if !reflect.DeepEqual(scan.Issues, want) {
+		t.Errorf("issues =\n%v\nwant\n%v", scan.Issues, want)
+	}
+}
+
+func TestScanMarkersOrphanEnd(t *testing.T) {
+	scan := ScanMarkers("a\n<!-- END X -->\n", "X")
+	want := []MarkerIssue{{Line: 2, Message: "END marker has no matching BEGIN marker"}}
+	if !reflect.DeepEqual(scan.Issues, want) {
+		t.Errorf("issues = %v, want %v", scan.Issues, want)
+	}
+}
+
+func TestScanMarkersIgnoresOtherMarkers(t *testing.T) {
+	scan := ScanMarkers("<!-- BEGIN XY -->\n<!-- END XY -->\n", "X")
+	if len(scan.Blocks) != 0 || len(scan.Issues) != 0 {
+		t.Errorf("unexpected scan result: %+v", scan)
+	}
+}
+
+func TestIsMarkerPolicy(t *testing.T) {
+	for _, p := range []string{MarkerPolicyError, MarkerPolicyFirst, MarkerPolicyAll} {
+		if !IsMarkerPolicy(p) {
+			t.Errorf("IsMarkerPolicy(%q) = false", p)
+		}
+	}
+	if IsMarkerPolicy("last") || IsMarkerPolicy("") {
+		t.Error("unexpected policy accepted")
+	}
+}
+
+func TestMarkerIssueString(t *testing.T) {
+	got := MarkerIssue{Line: 3, Message: "BEGIN marker is never closed"}.String()
+	if want := "line 3: BEGIN marker is never closed"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestScanMarkersAttributes(t *testing.T) {
+	content := "<!-- BEGIN X top=5 heading=\"Go projects\"  topics=go,cli -->\n<!-- END X -->\n"
+
+	scan := ScanMarkers(content, "X")
+	if len(scan.Issues) != 0 {
+		t.Fatalf("unexpected issues: %v", scan.Issues)
+	}
+	if len(scan.Blocks) != 1 {
+		t.Fatalf("got %d blocks, want 1", len(scan.Blocks))
+	}
+	want := map[string]string{"top": "5", "heading": "Go projects", "topics": "go,cli"}
+	if got := scan.Blocks[0].Attrs; !reflect.DeepEqual(got, want) {
+		t.Errorf("Attrs = %v, want %v", got, want)
+	}
+	if got := scan.Blocks[0].attrText; got != `top=5 heading="Go projects"  topics=go,cli` {
+		t.Errorf("attrText = %q", got)
+	}
+}
+
+func TestScanMarkersNoAttributes(t *testing.T) {
+	scan := ScanMarkers("<!-- BEGIN X -->\n<!-- END X -->\n<!-- BEGIN X-->\n<!-- END X -->\n", "X")
+	if len(scan.Issues) != 0 || len(scan.Blocks) != 2 {
+		t.Fatalf("unexpected scan result: %+v", scan)
+	}
+	for _, b := range scan.Blocks {
+		if b.Attrs != nil {
+			t.Errorf("Attrs = %v, want nil", b.Attrs)
+		}
+	}
+}
+
+func TestScanMarkersOtherMarkerWithSamePrefix(t *testing.T) {
+	// Words that are not attributes make the comment a different marker.
+	content := "<!-- BEGIN X ARCHIVE -->\n<!-- END X ARCHIVE -->\n<!-- BEGIN X top=1 -->\n<!-- END X -->\n"
+
+	scan := ScanMarkers(content, "X")
+	if len(scan.Issues) != 0 {
+		t.Fatalf("unexpected issues: %v", scan.Issues)
+	}
+	if len(scan.Blocks) != 1 || scan.Blocks[0].BeginLine != 3 {
+		t.Errorf("unexpected blocks: %+v", scan.Blocks)
+	}
+}
+
+func TestScanMarkersInvalidAttributes(t *testing.T) {
+	for _, tc := range []struct {
+		content string
+		want    string
+	}{
+		{"<!-- BEGIN X top=1 top=2 -->\n<!-- END X -->\n", `line 1: BEGIN marker attribute "top" is repeated`},
+		{"<!-- BEGIN X heading=\"Go -->\n<!-- END X -->\n", `line 1: BEGIN marker attribute "heading" has an unterminated quote`},
+		{"<!-- BEGIN X heading=\"Go\"x -->\n<!-- END X -->\n", `line 1: BEGIN marker attribute "heading" has text after its closing quote`},
+	} {
+		scan := ScanMarkers(tc.content, "X")
+		if len(scan.Issues) != 1 || scan.Issues[0].String() != tc.want {
+			t.Errorf("ScanMarkers(%q) issues = %v, want [%s]", tc.content, scan.Issues, tc.want)
+		}
+	}
+}
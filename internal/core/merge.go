@@ -0,0 +1,23 @@
+package core
+
+import "github.com/shinshin86/github-current-projects/internal/githubapi"
+
+// MergeRepos concatenates repository lists, dropping entries whose FullName
+// was already seen. The first occurrence wins and input order is preserved.
+// Repositories without a FullName are always kept.
+func MergeRepos(lists ...[]githubapi.Repository) []githubapi.Repository {
+	seen := make(map[string]struct{})
+	var result []githubapi.Repository
+	for _, list := range lists {
+		for _, r := range list {
+			if r.FullName != "" {
+				if _, ok := seen[r.FullName]; ok {
+					continue
+				}
+				seen[r.FullName] = struct{}{}
+			}
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+func TestMergeReposDedupesByFullName(t *testing.T) {
+	user := []githubapi.Repository{
+		{Name: "a", FullName: "u/a"},
+		{Name: "b", FullName: "org/b"},
+	}
+	org := []githubapi.Repository{
+		{Name: "b", FullName: "org/b", StargazersCount: 99},
+		{Name: "c", FullName: "org/c"},
+	}
+
+	result := MergeRepos(user, org)
+
+	if len(result) != 3 {
+		t.Fatalf("expected 3 repos, got %d", len(result))
+	}
+	if result[0].FullName != "u/a" || result[1].FullName != "org/b" || result[2].FullName != "org/c" {
+		t.Errorf("unexpected order: %+v", result)
+	}
+	if result[1].StargazersCount != 0 {
+		t.Error("first occurrence should win")
+	}
+}
+
+func TestMergeReposKeepsEmptyFullName(t *testing.T) {
+	result := MergeRepos(
+		[]githubapi.Repository{{Name: "x"}},
+		[]githubapi.Repository{{Name: "y"}},
+	)
+	if len(result) != 2 {
+		t.Errorf("expected 2 repos, got %d", len(result))
+	}
+}
+
+func TestMergeReposEmpty(t *testing.T) {
+	if result := MergeRepos(); len(result) != 0 {
+		t.Errorf("expected empty result, got %d", len(result))
+	}
+}
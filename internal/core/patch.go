@@ -8,25 +8,66 @@ import (
 // PatchResult holds the result of a README patch operation.
 type PatchResult struct {
 	Content string
+	// Patched reports whether the section was placed, by replacement or append.
 	Patched bool
+	// Changed reports whether Content differs from the existing content.
+	Changed bool
+	// Ignored lists the blocks left untouched under MarkerPolicyFirst.
+	Ignored []MarkerBlock
 }
 
 // PatchREADME replaces the marker section in the existing README content
 // with the new section. If appendIfMissing is true and markers are not found,
-// the section is appended at the end.
+// the section is appended at the end. Markers inside code blocks, HTML <pre>
+// blocks and inline code are ignored, so a README can document them.
+// More than one block for the marker is an error; see PatchREADMEWithOptions.
 func PatchREADME(existing, newSection, marker string, appendIfMissing bool) (PatchResult, error) {
-	beginMarker := fmt.Sprintf("<!-- BEGIN %s -->", marker)
-	endMarker := fmt.Sprintf("<!-- END %s -->", marker)
+	return PatchREADMEWithOptions(existing, newSection, PatchOptions{
+		Marker:          marker,
+		AppendIfMissing: appendIfMissing,
+	})
+}
+
+// PatchOptions controls PatchREADMEWithOptions.
+type PatchOptions struct {
+	Marker string
+	// AppendIfMissing appends the section when the README has no markers.
+	AppendIfMissing bool
+	// Policy handles several blocks for Marker: MarkerPolicyError (the
+	// default when empty), MarkerPolicyFirst or MarkerPolicyAll.
+	Policy string
+	// Render, if set, renders the section for a block whose BEGIN marker has
+	// attributes, replacing newSection for that block.
+	Render func(attrs map[string]string) (string, error)
+	// HTML treats existing as an HTML document, so markers are found on
+	// every line instead of being skipped inside Markdown code.
+	HTML bool
+}
+
+// PatchREADMEWithOptions is like PatchREADME with a configurable policy for
+// READMEs that contain the marker block more than once. Malformed markers
+// (see ScanMarkers) are an error under every policy. Attributes on a BEGIN
+// marker are written back unchanged when its block is replaced.
+func PatchREADMEWithOptions(existing, newSection string, opts PatchOptions) (PatchResult, error) {
+	marker := opts.Marker
 
 	// Detect line ending style
 	lineEnding := detectLineEnding(existing)
+	adapted := adaptLineEnding(newSection, lineEnding)
 
-	beginIdx := strings.Index(existing, beginMarker)
-	endIdx := strings.Index(existing, endMarker)
+	scan := scanMarkers(existing, marker, opts.HTML)
+	if len(scan.Issues) > 0 {
+		msgs := make([]string, len(scan.Issues))
+		for i, issue := range scan.Issues {
+			msgs[i] = issue.String()
+		}
+		return PatchResult{}, fmt.Errorf("README markers for %q are inconsistent: %s", marker, strings.Join(msgs, "; "))
+	}
 
-	if beginIdx == -1 && endIdx == -1 {
+	blocks := scan.Blocks
+	if len(blocks) == 0 {
 		// No markers found
-		if !appendIfMissing {
+		if !opts.AppendIfMissing {
 			return PatchResult{}, fmt.Errorf("marker %q not found in README; use --append-if-missing to add it", marker)
 		}
 		// Append at the end
@@ -36,41 +77,61 @@ func PatchREADME(existing, newSection, marker string, appendIfMissing bool) (Pat
 		} else if existing != "" {
 			separator = lineEnding
 		}
-		adapted := adaptLineEnding(newSection, lineEnding)
+		content := existing + separator + adapted
 		return PatchResult{
-			Content: existing + separator + adapted,
+			Content: content,
 			Patched: true,
+			Changed: content != existing,
 		}, nil
 	}
 
-	if beginIdx == -1 || endIdx == -1 {
-		return PatchResult{}, fmt.Errorf("only one of BEGIN/END markers for %q found; README markers are inconsistent", marker)
+	var ignored []MarkerBlock
+	if len(blocks) > 1 {
+		switch opts.Policy {
+		case MarkerPolicyAll:
+		case MarkerPolicyFirst:
+			blocks, ignored = blocks[:1], blocks[1:]
+		default:
+			return PatchResult{}, fmt.Errorf("marker %q appears in %d blocks (BEGIN at lines %s); remove the extras or use --marker-policy first or all", marker, len(blocks), blockLines(blocks))
+		}
 	}
 
-	if beginIdx > endIdx {
-		return PatchResult{}, fmt.Errorf("END marker appears before BEGIN marker for %q", marker)
+	sections := make([]string, len(blocks))
+	for i, b := range blocks {
+		section := adapted
+		if b.Attrs != nil && opts.Render != nil {
+			rendered, err := opts.Render(b.Attrs)
+			if err != nil {
+				return PatchResult{}, fmt.Errorf("marker %q at line %d: %w", marker, b.BeginLine, err)
+			}
+			section = adaptLineEnding(rendered, lineEnding)
+		}
+		sections[i] = withBeginAttrs(section, marker, b.attrText)
 	}
 
-	// Find the end of the END marker line
-	endOfEndMarker := endIdx + len(endMarker)
-	if endOfEndMarker < len(existing) && existing[endOfEndMarker] == '\r' {
-		endOfEndMarker++
-	}
-	if endOfEndMarker < len(existing) && existing[endOfEndMarker] == '\n' {
-		endOfEndMarker++
+	// Replace from the last block so earlier offsets stay valid
+	content := existing
+	for i := len(blocks) - 1; i >= 0; i-- {
+		content = content[:blocks[i].start] + sections[i] + content[blocks[i].end:]
 	}
 
-	before := existing[:beginIdx]
-	after := existing[endOfEndMarker:]
-
-	adapted := adaptLineEnding(newSection, lineEnding)
-
 	return PatchResult{
-		Content: before + adapted + after,
+		Content: content,
 		Patched: true,
+		Changed: content != existing,
+		Ignored: ignored,
 	}, nil
 }
 
+// withBeginAttrs adds attrText to the BEGIN marker of section.
+func withBeginAttrs(section, marker, attrText string) string {
+	if attrText == "" {
+		return section
+	}
+	begin := fmt.Sprintf("<!-- BEGIN %s -->", marker)
+	return strings.Replace(section, begin, fmt.Sprintf("<!-- BEGIN %s %s -->", marker, attrText), 1)
+}
+
 // detectLineEnding returns "\r\n" if the content uses CRLF, otherwise "\n".
 func detectLineEnding(content string) string {
 	if strings.Contains(content, "\r\n") {
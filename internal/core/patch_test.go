@@ -1,6 +1,7 @@
 package core
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -30,6 +31,9 @@ func TestPatchREADMENormal(t *testing.T) {
 	if !result.Patched {
 		t.Error("expected Patched=true")
 	}
+	if !result.Changed {
+		t.Error("expected Changed=true")
+	}
 	if !strings.Contains(result.Content, "[new]") {
 		t.Error("new section not found")
 	}
@@ -148,3 +152,214 @@ func TestPatchREADMEPreservesLF(t *testing.T) {
 		t.Error("LF content should not gain CRLF")
 	}
 }
+
+func TestPatchREADMEUnchanged(t *testing.T) {
+	section := "<!-- BEGIN CURRENT PROJECTS -->\n## Current Projects\n<!-- END CURRENT PROJECTS -->\n"
+	existing := "# Profile\r\n\r\n" + strings.ReplaceAll(section, "\n", "\r\n") + "\r\n## About\r\n"
+
+	result, err := PatchREADME(existing, section, "CURRENT PROJECTS", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Patched {
+		t.Error("expected Patched=true")
+	}
+	if result.Changed {
+		t.Error("expected Changed=false when the section is already current")
+	}
+	if result.Content != existing {
+		t.Errorf("content should be unchanged:\n%q", result.Content)
+	}
+}
+
+func TestPatchREADMEIgnoresMarkersInCode(t *testing.T) {
+	existing := "# Profile\n\n" +
+		"Write `<!-- BEGIN CURRENT PROJECTS -->` in your README:\n\n" +
+		"```markdown\n<!-- BEGIN CURRENT PROJECTS -->\n<!-- END CURRENT PROJECTS -->\n```\n\n" +
+		"    <!-- BEGIN CURRENT PROJECTS -->\n    <!-- END CURRENT PROJECTS -->\n\n" +
+		"<pre>\n<!-- BEGIN CURRENT PROJECTS -->\n</pre>\n\n" +
+		"<!-- BEGIN CURRENT PROJECTS -->\n- [old](https://github.com/u/old)\n<!-- END CURRENT PROJECTS -->\n"
+	newSection := "<!-- BEGIN CURRENT PROJECTS -->\n- [new](https://github.com/u/new)\n<!-- END CURRENT PROJECTS -->\n"
+
+	result, err := PatchREADME(existing, newSection, "CURRENT PROJECTS", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := strings.Replace(existing, "- [old](https://github.com/u/old)", "- [new](https://github.com/u/new)", 1)
+	if result.Content != want {
+		t.Errorf("content mismatch:\ngot:\n%s\nwant:\n%s", result.Content, want)
+	}
+}
+
+func TestPatchREADMEMarkersOnlyInCode(t *testing.T) {
+	existing := "# Profile\n\n```\n<!-- BEGIN CURRENT PROJECTS -->\n<!-- END CURRENT PROJECTS -->\n```\n"
+	newSection := "<!-- BEGIN CURRENT PROJECTS -->\n<!-- END CURRENT PROJECTS -->\n"
+
+	if _, err := PatchREADME(existing, newSection, "CURRENT PROJECTS", false); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	result, err := PatchREADME(existing, newSection, "CURRENT PROJECTS", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content != existing+"\n"+newSection {
+		t.Errorf("section should be appended after the code block, got:\n%s", result.Content)
+	}
+}
+
+func TestPatchREADMEHTMLIndentedMarkers(t *testing.T) {
+	existing := "<main>\n\n    <h1>Projects</h1>\n\n" +
+		"    <!-- BEGIN CURRENT PROJECTS -->\n    <ul><li>old</li></ul>\n    <!-- END CURRENT PROJECTS -->\n\n" +
+		"    <pre>\n    <!-- BEGIN OTHER -->\n    </pre>\n</main>\n"
+	newSection := "<!-- BEGIN CURRENT PROJECTS -->\n<ul><li>new</li></ul>\n<!-- END CURRENT PROJECTS -->\n"
+
+	// As Markdown, the indented markers are an indented code block.
+	if _, err := PatchREADME(existing, newSection, "CURRENT PROJECTS", false); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error for Markdown, got %v", err)
+	}
+
+	result, err := PatchREADMEWithOptions(existing, newSection, PatchOptions{Marker: "CURRENT PROJECTS", HTML: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "<main>\n\n    <h1>Projects</h1>\n\n" +
+		"    <!-- BEGIN CURRENT PROJECTS -->\n<ul><li>new</li></ul>\n<!-- END CURRENT PROJECTS -->\n\n" +
+		"    <pre>\n    <!-- BEGIN OTHER -->\n    </pre>\n</main>\n"
+	if result.Content != want {
+		t.Errorf("content mismatch:\ngot:\n%s\nwant:\n%s", result.Content, want)
+	}
+}
+
+func TestPatchREADMEHTMLMarkerInPre(t *testing.T) {
+	// A comment inside <pre> is still a comment in HTML.
+	existing := "<pre>\n<!-- BEGIN CURRENT PROJECTS -->\nold\n<!-- END CURRENT PROJECTS -->\n</pre>\n"
+	newSection := "<!-- BEGIN CURRENT PROJECTS -->\nnew\n<!-- END CURRENT PROJECTS -->\n"
+
+	result, err := PatchREADMEWithOptions(existing, newSection, PatchOptions{Marker: "CURRENT PROJECTS", HTML: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := strings.Replace(existing, "old", "new", 1); result.Content != want {
+		t.Errorf("content = %q, want %q", result.Content, want)
+	}
+}
+
+const duplicateBlocks = "# Profile\n" +
+	"<!-- BEGIN CURRENT PROJECTS -->\nold1\n<!-- END CURRENT PROJECTS -->\n" +
+	"\n## Elsewhere\n" +
+	"<!-- BEGIN CURRENT PROJECTS -->\nold2\n<!-- END CURRENT PROJECTS -->\n"
+
+func TestPatchREADMEDuplicateBlocksError(t *testing.T) {
+	newSection := "<!-- BEGIN CURRENT PROJECTS -->\nnew\n<!-- END CURRENT PROJECTS -->\n"
+
+	_, err := PatchREADME(duplicateBlocks, newSection, "CURRENT PROJECTS", false)
+	if err == nil {
+		t.Fatal("expected error for duplicate blocks")
+	}
+	if !strings.Contains(err.Error(), "2 blocks") || !strings.Contains(err.Error(), "lines 2, 7") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestPatchREADMEDuplicateBlocksFirst(t *testing.T) {
+	newSection := "<!-- BEGIN CURRENT PROJECTS -->\nnew\n<!-- END CURRENT PROJECTS -->\n"
+
+	result, err := PatchREADMEWithOptions(duplicateBlocks, newSection, PatchOptions{
+		Marker: "CURRENT PROJECTS",
+		Policy: MarkerPolicyFirst,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := strings.Replace(duplicateBlocks, "old1", "new", 1)
+	if result.Content != want {
+		t.Errorf("content mismatch:\ngot:\n%s\nwant:\n%s", result.Content, want)
+	}
+	if len(result.Ignored) != 1 || result.Ignored[0].BeginLine != 7 || result.Ignored[0].EndLine != 9 {
+		t.Errorf("Ignored = %+v, want one block at lines 7-9", result.Ignored)
+	}
+}
+
+func TestPatchREADMEDuplicateBlocksAll(t *testing.T) {
+	newSection := "<!-- BEGIN CURRENT PROJECTS -->\nnew\nlines\n<!-- END CURRENT PROJECTS -->\n"
+
+	result, err := PatchREADMEWithOptions(duplicateBlocks, newSection, PatchOptions{
+		Marker: "CURRENT PROJECTS",
+		Policy: MarkerPolicyAll,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := strings.NewReplacer("old1", "new\nlines", "old2", "new\nlines").Replace(duplicateBlocks)
+	if result.Content != want {
+		t.Errorf("content mismatch:\ngot:\n%s\nwant:\n%s", result.Content, want)
+	}
+	if len(result.Ignored) != 0 {
+		t.Errorf("Ignored = %+v, want none", result.Ignored)
+	}
+}
+
+func TestPatchREADMENestedMarkers(t *testing.T) {
+	existing := "<!-- BEGIN CURRENT PROJECTS -->\n<!-- BEGIN CURRENT PROJECTS -->\n<!-- END CURRENT PROJECTS -->\n"
+
+	for _, policy := range []string{MarkerPolicyError, MarkerPolicyFirst, MarkerPolicyAll} {
+		_, err := PatchREADMEWithOptions(existing, "new\n", PatchOptions{Marker: "CURRENT PROJECTS", Policy: policy})
+		if err == nil || !strings.Contains(err.Error(), "line 2: BEGIN marker is nested") {
+			t.Errorf("policy %s: expected nesting error, got %v", policy, err)
+		}
+	}
+}
+
+func TestPatchREADMEPreservesMarkerAttributes(t *testing.T) {
+	existing := "# Profile\r\n<!-- BEGIN CURRENT PROJECTS top=5 sort=stars -->\r\nold\r\n<!-- END CURRENT PROJECTS -->\r\n"
+	newSection := "<!-- BEGIN CURRENT PROJECTS -->\nnew\n<!-- END CURRENT PROJECTS -->\n"
+
+	result, err := PatchREADME(existing, newSection, "CURRENT PROJECTS", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# Profile\r\n<!-- BEGIN CURRENT PROJECTS top=5 sort=stars -->\r\nnew\r\n<!-- END CURRENT PROJECTS -->\r\n"
+	if result.Content != want {
+		t.Errorf("content mismatch:\ngot:  %q\nwant: %q", result.Content, want)
+	}
+}
+
+func TestPatchREADMERenderWithAttributes(t *testing.T) {
+	existing := "<!-- BEGIN X top=1 -->\nold\n<!-- END X -->\n\n<!-- BEGIN X -->\nold\n<!-- END X -->\n"
+	newSection := "<!-- BEGIN X -->\ndefault\n<!-- END X -->\n"
+
+	var calls []map[string]string
+	result, err := PatchREADMEWithOptions(existing, newSection, PatchOptions{
+		Marker: "X",
+		Policy: MarkerPolicyAll,
+		Render: func(attrs map[string]string) (string, error) {
+			calls = append(calls, attrs)
+			return "<!-- BEGIN X -->\ntop " + attrs["top"] + "\n<!-- END X -->\n", nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "<!-- BEGIN X top=1 -->\ntop 1\n<!-- END X -->\n\n<!-- BEGIN X -->\ndefault\n<!-- END X -->\n"
+	if result.Content != want {
+		t.Errorf("content mismatch:\ngot:\n%s\nwant:\n%s", result.Content, want)
+	}
+	if len(calls) != 1 {
+		t.Errorf("Render called %d times, want 1 (only for the block with attributes)", len(calls))
+	}
+}
+
+func TestPatchREADMERenderError(t *testing.T) {
+	existing := "# Profile\n<!-- BEGIN X top=bad -->\n<!-- END X -->\n"
+
+	_, err := PatchREADMEWithOptions(existing, "new\n", PatchOptions{
+		Marker: "X",
+		Render: func(attrs map[string]string) (string, error) {
+			return "", errors.New("attribute top: invalid value")
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), `marker "X" at line 2: attribute top`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
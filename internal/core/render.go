@@ -5,22 +5,80 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/shinshin86/github-current-projects/internal/githubapi"
 )
 
+// Private repository rendering styles.
+const (
+	// PrivateStyleNoLink labels private repos and omits their links.
+	PrivateStyleNoLink = "nolink"
+	// PrivateStyleLabel labels private repos but keeps their links.
+	PrivateStyleLabel = "label"
+)
+
+// MarkdownOptions controls optional Markdown rendering behavior.
+type MarkdownOptions struct {
+	// Heading is the section heading text. Empty means "Current Projects".
+	Heading string
+	// PrivateStyle selects how private repositories are shown.
+	// Empty means PrivateStyleNoLink.
+	PrivateStyle string
+	// PinnedCount is the number of leading repos rendered under a separate
+	// "Pinned" subsection. Zero renders a single flat list.
+	PinnedCount int
+}
+
 // RenderMarkdown produces the Markdown section for the given repos.
 func RenderMarkdown(repos []githubapi.Repository, marker string) string {
+	return RenderMarkdownWithOptions(repos, marker, MarkdownOptions{})
+}
+
+// RenderMarkdownWithOptions produces the Markdown section using the given options.
+func RenderMarkdownWithOptions(repos []githubapi.Repository, marker string, opts MarkdownOptions) string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("<!-- BEGIN %s -->\n", marker))
-	sb.WriteString("## Current Projects\n\n")
+	writeHeading(&sb, opts.Heading)
+
+	pinned := opts.PinnedCount
+	if pinned > len(repos) {
+		pinned = len(repos)
+	}
 
-	if len(repos) == 0 {
+	switch {
+	case len(repos) == 0:
+		sb.WriteString("_No public projects matched._\n")
+	case pinned > 0:
+		sb.WriteString("### Pinned\n\n")
+		writeRepoLines(&sb, repos[:pinned], opts)
+		if pinned < len(repos) {
+			sb.WriteString("\n### More Projects\n\n")
+			writeRepoLines(&sb, repos[pinned:], opts)
+		}
+	default:
+		writeRepoLines(&sb, repos, opts)
+	}
+
+	sb.WriteString(fmt.Sprintf("<!-- END %s -->\n", marker))
+	return sb.String()
+}
+
+// RenderMarkdownGroups produces the Markdown section with a sub-heading per group.
+func RenderMarkdownGroups(groups []RepoGroup, marker string, opts MarkdownOptions) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<!-- BEGIN %s -->\n", marker))
+	writeHeading(&sb, opts.Heading)
+
+	if len(groups) == 0 {
 		sb.WriteString("_No public projects matched._\n")
 	} else {
-		for _, r := range repos {
-			sb.WriteString(formatRepoLine(r))
-			sb.WriteByte('\n')
+		for i, g := range groups {
+			if i > 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(fmt.Sprintf("### %s\n\n", escapeMarkdownInline(normalizeInlineText(g.Name))))
+			writeRepoLines(&sb, g.Repos, opts)
 		}
 	}
 
@@ -28,9 +86,25 @@ func RenderMarkdown(repos []githubapi.Repository, marker string) string {
 	return sb.String()
 }
 
-func formatRepoLine(r githubapi.Repository) string {
+// writeHeading writes the section heading, defaulting to "Current Projects".
+func writeHeading(sb *strings.Builder, heading string) {
+	heading = strings.TrimSpace(heading)
+	if heading == "" {
+		heading = "Current Projects"
+	}
+	sb.WriteString(fmt.Sprintf("## %s\n\n", escapeMarkdownInline(normalizeInlineText(heading))))
+}
+
+func writeRepoLines(sb *strings.Builder, repos []githubapi.Repository, opts MarkdownOptions) {
+	for _, r := range repos {
+		sb.WriteString(formatRepoLine(r, opts))
+		sb.WriteByte('\n')
+	}
+}
+
+func formatRepoLine(r githubapi.Repository, opts MarkdownOptions) string {
 	name := escapeMarkdownInline(strings.TrimSpace(r.Name))
-	link := sanitizeMarkdownURL(r.HTMLURL)
+	link := repoLink(r, opts.PrivateStyle)
 	language := escapeMarkdownInline(strings.TrimSpace(r.Language))
 	description := escapeMarkdownInline(normalizeInlineText(r.Description))
 
@@ -41,6 +115,10 @@ func formatRepoLine(r githubapi.Repository) string {
 		parts = append(parts, fmt.Sprintf("- %s", name))
 	}
 
+	if r.Private {
+		parts = append(parts, "(private)")
+	}
+
 	if language != "" {
 		parts = append(parts, fmt.Sprintf("(%s)", language))
 	}
@@ -52,6 +130,15 @@ func formatRepoLine(r githubapi.Repository) string {
 	return strings.Join(parts, " ")
 }
 
+// repoLink returns the sanitized URL of r, or "" if it is unsafe or r is
+// private and privateStyle hides private links.
+func repoLink(r githubapi.Repository, privateStyle string) string {
+	if r.Private && privateStyle != PrivateStyleLabel {
+		return ""
+	}
+	return sanitizeMarkdownURL(r.HTMLURL)
+}
+
 var markdownInlineEscaper = strings.NewReplacer(
 	"&", "&amp;",
 	"<", "&lt;",
@@ -98,7 +185,110 @@ func sanitizeMarkdownURL(raw string) string {
 	return strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(u.String())
 }
 
-// JSONOutput is a single repo entry in JSON output mode.
+// JSONSchemaVersion is the version of the JSON envelope produced by
+// RenderJSONEnvelope. It is incremented on incompatible changes.
+const JSONSchemaVersion = 1
+
+// jsonTimeFormat is the timestamp format used in all JSON output.
+const jsonTimeFormat = "2006-01-02T15:04:05Z"
+
+// JSONEnvelope is the versioned top-level object of JSON output.
+type JSONEnvelope struct {
+	SchemaVersion int             `json:"schema_version"`
+	GeneratedAt   string          `json:"generated_at"`
+	User          string          `json:"user"`
+	Filters       TemplateFilters `json:"filters"`
+	Repos         []JSONRepo      `json:"repos"`
+	// Groups is present only when grouping is enabled.
+	Groups []JSONRepoGroup `json:"groups,omitempty"`
+}
+
+// JSONRepo is a repository entry in the JSON envelope, with every field of
+// githubapi.Repository.
+type JSONRepo struct {
+	Name            string                     `json:"name"`
+	FullName        string                     `json:"full_name"`
+	HTMLURL         string                     `json:"html_url"`
+	Description     string                     `json:"description"`
+	Topics          []string                   `json:"topics"`
+	Fork            bool                       `json:"fork"`
+	Archived        bool                       `json:"archived"`
+	Private         bool                       `json:"private"`
+	Language        string                     `json:"language"`
+	StargazersCount int                        `json:"stargazers_count"`
+	PushedAt        *string                    `json:"pushed_at"`
+	Extra           map[string]json.RawMessage `json:"extra,omitempty"`
+}
+
+// JSONRepoGroup is a group entry in the JSON envelope.
+type JSONRepoGroup struct {
+	Group string     `json:"group"`
+	Repos []JSONRepo `json:"repos"`
+}
+
+// JSONEnvelopeOptions describes how the repos in a JSON envelope were produced.
+type JSONEnvelopeOptions struct {
+	// GeneratedAt is the generation time. Zero means time.Now().
+	GeneratedAt time.Time
+	User        string
+	Filters     TemplateFilters
+	// Groups, when non-nil, is reported alongside the flat repo list.
+	Groups []RepoGroup
+}
+
+// RenderJSONEnvelope produces the versioned JSON envelope for the given repos.
+func RenderJSONEnvelope(repos []githubapi.Repository, opts JSONEnvelopeOptions) (string, error) {
+	generatedAt := opts.GeneratedAt
+	if generatedAt.IsZero() {
+		generatedAt = time.Now()
+	}
+	filters := opts.Filters
+	if filters.Topics == nil {
+		filters.Topics = []string{}
+	}
+
+	env := JSONEnvelope{
+		SchemaVersion: JSONSchemaVersion,
+		GeneratedAt:   generatedAt.UTC().Format(jsonTimeFormat),
+		User:          opts.User,
+		Filters:       filters,
+		Repos:         toJSONRepos(repos),
+	}
+	for _, g := range opts.Groups {
+		env.Groups = append(env.Groups, JSONRepoGroup{Group: g.Name, Repos: toJSONRepos(g.Repos)})
+	}
+	return marshalJSON(env)
+}
+
+func toJSONRepos(repos []githubapi.Repository) []JSONRepo {
+	out := make([]JSONRepo, len(repos))
+	for i, r := range repos {
+		topics := r.Topics
+		if topics == nil {
+			topics = []string{}
+		}
+		out[i] = JSONRepo{
+			Name:            r.Name,
+			FullName:        r.FullName,
+			HTMLURL:         r.HTMLURL,
+			Description:     r.Description,
+			Topics:          topics,
+			Fork:            r.Fork,
+			Archived:        r.Archived,
+			Private:         r.Private,
+			Language:        r.Language,
+			StargazersCount: r.StargazersCount,
+			Extra:           r.Extra,
+		}
+		if !r.PushedAt.IsZero() {
+			pushed := r.PushedAt.UTC().Format(jsonTimeFormat)
+			out[i].PushedAt = &pushed
+		}
+	}
+	return out
+}
+
+// JSONOutput is a single repo entry in the legacy JSON array output.
 type JSONOutput struct {
 	Name            string `json:"name"`
 	HTMLURL         string `json:"html_url"`
@@ -108,8 +298,27 @@ type JSONOutput struct {
 	StargazersCount int    `json:"stargazers_count"`
 }
 
-// RenderJSON produces a JSON array string for the given repos.
+// RenderJSON produces the legacy JSON array for the given repos.
 func RenderJSON(repos []githubapi.Repository) (string, error) {
+	return marshalJSON(toJSONOutputs(repos))
+}
+
+// JSONGroup is a single group entry in legacy grouped JSON output.
+type JSONGroup struct {
+	Group string       `json:"group"`
+	Repos []JSONOutput `json:"repos"`
+}
+
+// RenderJSONGroups produces a JSON array of groups, each holding its repos.
+func RenderJSONGroups(groups []RepoGroup) (string, error) {
+	out := make([]JSONGroup, len(groups))
+	for i, g := range groups {
+		out[i] = JSONGroup{Group: g.Name, Repos: toJSONOutputs(g.Repos)}
+	}
+	return marshalJSON(out)
+}
+
+func toJSONOutputs(repos []githubapi.Repository) []JSONOutput {
 	out := make([]JSONOutput, len(repos))
 	for i, r := range repos {
 		out[i] = JSONOutput{
@@ -117,11 +326,15 @@ func RenderJSON(repos []githubapi.Repository) (string, error) {
 			HTMLURL:         r.HTMLURL,
 			Description:     r.Description,
 			Language:        r.Language,
-			PushedAt:        r.PushedAt.Format("2006-01-02T15:04:05Z"),
+			PushedAt:        r.PushedAt.Format(jsonTimeFormat),
 			StargazersCount: r.StargazersCount,
 		}
 	}
-	data, err := json.MarshalIndent(out, "", "  ")
+	return out
+}
+
+func marshalJSON(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("marshaling JSON: %w", err)
 	}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -171,3 +172,127 @@ func TestRenderMarkdownRejectsNonHTTPLinkScheme(t *testing.T) {
 		t.Fatalf("expected non-link fallback line: %s", result)
 	}
 }
+
+func TestRenderMarkdownPrivateOmitsLinkByDefault(t *testing.T) {
+	repos := []githubapi.Repository{
+		{
+			Name:        "secret",
+			HTMLURL:     "https://github.com/u/secret",
+			Description: "internal",
+			Private:     true,
+		},
+	}
+
+	result := RenderMarkdown(repos, "CURRENT PROJECTS")
+
+	if strings.Contains(result, "https://github.com/u/secret") {
+		t.Fatalf("private link should be omitted: %s", result)
+	}
+	if !strings.Contains(result, "- secret (private) - internal") {
+		t.Fatalf("expected private label: %s", result)
+	}
+}
+
+func TestRenderMarkdownPrivateLabelKeepsLink(t *testing.T) {
+	repos := []githubapi.Repository{
+		{
+			Name:    "secret",
+			HTMLURL: "https://github.com/u/secret",
+			Private: true,
+		},
+	}
+
+	result := RenderMarkdownWithOptions(repos, "CURRENT PROJECTS", MarkdownOptions{PrivateStyle: PrivateStyleLabel})
+
+	if !strings.Contains(result, "- [secret](https://github.com/u/secret) (private)\n") {
+		t.Fatalf("expected labeled link: %s", result)
+	}
+}
+
+func TestRenderMarkdownPinnedSection(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "pinned-one", HTMLURL: "https://github.com/u/pinned-one"},
+		{Name: "other", HTMLURL: "https://github.com/u/other"},
+	}
+
+	result := RenderMarkdownWithOptions(repos, "CURRENT PROJECTS", MarkdownOptions{PinnedCount: 1})
+
+	pinnedIdx := strings.Index(result, "### Pinned\n\n- [pinned-one]")
+	moreIdx := strings.Index(result, "### More Projects\n\n- [other]")
+	if pinnedIdx == -1 || moreIdx == -1 || pinnedIdx > moreIdx {
+		t.Fatalf("expected Pinned then More Projects subsections: %s", result)
+	}
+}
+
+func TestRenderMarkdownPinnedSectionAllPinned(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "only", HTMLURL: "https://github.com/u/only"},
+	}
+
+	result := RenderMarkdownWithOptions(repos, "CURRENT PROJECTS", MarkdownOptions{PinnedCount: 5})
+
+	if !strings.Contains(result, "### Pinned") {
+		t.Errorf("missing Pinned subsection: %s", result)
+	}
+	if strings.Contains(result, "### More Projects") {
+		t.Errorf("More Projects should be omitted when everything is pinned: %s", result)
+	}
+}
+
+func TestRenderMarkdownCustomHeading(t *testing.T) {
+	result := RenderMarkdownWithOptions(nil, "STARRED", MarkdownOptions{Heading: "Most starred"})
+
+	if !strings.Contains(result, "<!-- BEGIN STARRED -->\n## Most starred\n") {
+		t.Errorf("missing custom heading: %s", result)
+	}
+	if strings.Contains(result, "Current Projects") {
+		t.Errorf("default heading should be replaced: %s", result)
+	}
+}
+
+func TestRenderJSONEnvelope(t *testing.T) {
+	repos := []githubapi.Repository{
+		{
+			Name:     "test",
+			FullName: "u/test",
+			Fork:     true,
+			PushedAt: time.Date(2025, 1, 15, 19, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+		},
+		{Name: "unknown"},
+	}
+
+	result, err := RenderJSONEnvelope(repos, JSONEnvelopeOptions{
+		GeneratedAt: time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC),
+		User:        "u",
+		Filters:     TemplateFilters{Top: 5, TagMatch: "any", Sort: "stars"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var env JSONEnvelope
+	if err := json.Unmarshal([]byte(result), &env); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, result)
+	}
+	if env.SchemaVersion != JSONSchemaVersion || env.GeneratedAt != "2025-02-01T12:00:00Z" || env.User != "u" {
+		t.Errorf("unexpected envelope: %+v", env)
+	}
+	if env.Filters.Top != 5 || env.Filters.Sort != "stars" || env.Filters.Topics == nil {
+		t.Errorf("unexpected filters: %+v", env.Filters)
+	}
+	if len(env.Repos) != 2 || env.Repos[0].FullName != "u/test" || !env.Repos[0].Fork {
+		t.Fatalf("unexpected repos: %+v", env.Repos)
+	}
+	if env.Repos[0].PushedAt == nil || *env.Repos[0].PushedAt != "2025-01-15T10:00:00Z" {
+		t.Errorf("pushed_at should be normalized to UTC: %v", env.Repos[0].PushedAt)
+	}
+	if env.Repos[1].PushedAt != nil {
+		t.Errorf("unknown pushed_at should be null: %v", *env.Repos[1].PushedAt)
+	}
+	if !strings.Contains(result, `"topics": []`) {
+		t.Errorf("topics should be an empty array, not null:\n%s", result)
+	}
+	if strings.Contains(result, `"groups"`) || strings.Contains(result, `"extra"`) {
+		t.Errorf("groups and extra should be omitted:\n%s", result)
+	}
+}
@@ -49,3 +49,43 @@ func TopN(repos []githubapi.Repository, n int) []githubapi.Repository {
 	}
 	return repos[:n]
 }
+
+// PinFirst moves the repositories listed in pinned to the front of repos,
+// in pinned order, keeping the relative order of the rest. Matching is by
+// FullName (falling back to Name when FullName is empty); pinned entries not
+// present in repos are ignored. It returns the reordered slice and the number
+// of pinned repositories at its front.
+func PinFirst(repos, pinned []githubapi.Repository) ([]githubapi.Repository, int) {
+	index := make(map[string]int, len(repos))
+	for i, r := range repos {
+		index[repoKey(r)] = i
+	}
+
+	used := make(map[int]struct{}, len(pinned))
+	result := make([]githubapi.Repository, 0, len(repos))
+	for _, p := range pinned {
+		i, ok := index[repoKey(p)]
+		if !ok {
+			continue
+		}
+		if _, dup := used[i]; dup {
+			continue
+		}
+		used[i] = struct{}{}
+		result = append(result, repos[i])
+	}
+	n := len(result)
+	for i, r := range repos {
+		if _, ok := used[i]; !ok {
+			result = append(result, r)
+		}
+	}
+	return result, n
+}
+
+func repoKey(r githubapi.Repository) string {
+	if r.FullName != "" {
+		return strings.ToLower(r.FullName)
+	}
+	return strings.ToLower(r.Name)
+}
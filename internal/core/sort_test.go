@@ -1,6 +1,7 @@
 package core
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -93,3 +94,38 @@ func TestTopN(t *testing.T) {
 		t.Errorf("TopN(5, 10) = %d items, want 5", len(result))
 	}
 }
+
+func TestPinFirst(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "a", FullName: "u/a"},
+		{Name: "b", FullName: "u/b"},
+		{Name: "c", FullName: "u/c"},
+		{Name: "d", FullName: "u/d"},
+	}
+	pinned := []githubapi.Repository{
+		{Name: "c", FullName: "u/c"},
+		{Name: "gone", FullName: "u/gone"},
+		{Name: "a", FullName: "U/A"},
+	}
+
+	result, n := PinFirst(repos, pinned)
+
+	if n != 2 {
+		t.Errorf("pinned count = %d, want 2", n)
+	}
+	var names []string
+	for _, r := range result {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "c,a,b,d" {
+		t.Errorf("order = %s, want c,a,b,d", got)
+	}
+}
+
+func TestPinFirstNoPinned(t *testing.T) {
+	repos := []githubapi.Repository{{Name: "a"}, {Name: "b"}}
+	result, n := PinFirst(repos, nil)
+	if n != 0 || len(result) != 2 || result[0].Name != "a" {
+		t.Errorf("unexpected result: n=%d %+v", n, result)
+	}
+}
@@ -0,0 +1,278 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+// SVG themes.
+const (
+	SVGThemeLight = "light"
+	SVGThemeDark  = "dark"
+)
+
+// SVG size defaults and limits.
+const (
+	DefaultSVGWidth            = 495
+	MinSVGWidth                = 300
+	MaxSVGWidth                = 1200
+	DefaultSVGDescriptionLines = 2
+)
+
+// SVGOptions controls SVG card rendering.
+type SVGOptions struct {
+	// Heading is the card title. Empty means "Current Projects".
+	Heading string
+	// Theme is SVGThemeLight (the default) or SVGThemeDark.
+	Theme string
+	// Width is the card width in pixels. Zero means DefaultSVGWidth.
+	Width int
+	// DescriptionLines limits each description to this many wrapped lines;
+	// longer text is cut with "…". Zero means DefaultSVGDescriptionLines.
+	DescriptionLines int
+}
+
+type svgTheme struct {
+	Background string
+	Border     string
+	Title      string
+	Name       string
+	Text       string
+	Muted      string
+}
+
+var svgThemes = map[string]svgTheme{
+	SVGThemeLight: {
+		Background: "#ffffff",
+		Border:     "#d0d7de",
+		Title:      "#1f2328",
+		Name:       "#0969da",
+		Text:       "#1f2328",
+		Muted:      "#656d76",
+	},
+	SVGThemeDark: {
+		Background: "#0d1117",
+		Border:     "#30363d",
+		Title:      "#e6edf3",
+		Name:       "#4493f8",
+		Text:       "#e6edf3",
+		Muted:      "#8d96a0",
+	},
+}
+
+// IsSVGTheme reports whether name is a supported SVG theme.
+func IsSVGTheme(name string) bool {
+	_, ok := svgThemes[name]
+	return ok
+}
+
+// languageColors holds GitHub's colors for common languages.
+var languageColors = map[string]string{
+	"C":                "#555555",
+	"C#":               "#178600",
+	"C++":              "#f34b7d",
+	"CSS":              "#563d7c",
+	"Dart":             "#00b4ab",
+	"Dockerfile":       "#384d54",
+	"Elixir":           "#6e4a7e",
+	"Go":               "#00add8",
+	"HTML":             "#e34c26",
+	"Haskell":          "#5e5086",
+	"Java":             "#b07219",
+	"JavaScript":       "#f1e05a",
+	"Jupyter Notebook": "#da5b0b",
+	"Kotlin":           "#a97bff",
+	"Lua":              "#000080",
+	"PHP":              "#4f5d95",
+	"Python":           "#3572a5",
+	"Ruby":             "#701516",
+	"Rust":             "#dea584",
+	"Scala":            "#c22d40",
+	"Shell":            "#89e051",
+	"Swift":            "#f05138",
+	"TypeScript":       "#3178c6",
+	"Vue":              "#41b883",
+	"Zig":              "#ec915c",
+}
+
+const defaultLanguageColor = "#8b949e"
+
+// SVG layout metrics, in pixels.
+const (
+	svgPadding     = 20
+	svgTitleHeight = 36
+	svgNameHeight  = 20
+	svgLineHeight  = 16
+	svgMetaHeight  = 22
+	svgRepoGap     = 10
+	svgCharWidth   = 6.6 // average width of a 12px sans-serif character
+)
+
+// RenderSVG produces a self-contained SVG card listing the given repos.
+func RenderSVG(repos []githubapi.Repository, opts SVGOptions) string {
+	theme, ok := svgThemes[opts.Theme]
+	if !ok {
+		theme = svgThemes[SVGThemeLight]
+	}
+	width := opts.Width
+	if width <= 0 {
+		width = DefaultSVGWidth
+	}
+	maxLines := opts.DescriptionLines
+	if maxLines <= 0 {
+		maxLines = DefaultSVGDescriptionLines
+	}
+	heading := normalizeInlineText(opts.Heading)
+	if heading == "" {
+		heading = "Current Projects"
+	}
+	wrapWidth := int(float64(width-2*svgPadding) / svgCharWidth)
+
+	var body strings.Builder
+	y := svgPadding + svgTitleHeight
+	if len(repos) == 0 {
+		body.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" class="muted">No public projects matched.</text>`+"\n", svgPadding, y+svgLineHeight))
+		y += svgNameHeight
+	}
+	for _, r := range repos {
+		name := strings.TrimSpace(r.Name)
+		if r.Private {
+			name += " (private)"
+		}
+		y += svgNameHeight
+		body.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" class="name">%s</text>`+"\n", svgPadding, y-4, escapeXML(name)))
+		body.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" class="muted" text-anchor="end">★ %d</text>`+"\n", width-svgPadding, y-4, r.StargazersCount))
+
+		for _, line := range wrapText(normalizeInlineText(r.Description), wrapWidth, maxLines) {
+			y += svgLineHeight
+			body.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" class="text">%s</text>`+"\n", svgPadding, y-4, escapeXML(line)))
+		}
+
+		y += svgMetaHeight
+		x := svgPadding
+		if lang := strings.TrimSpace(r.Language); lang != "" {
+			body.WriteString(fmt.Sprintf(`  <circle cx="%d" cy="%d" r="5" fill="%s"/>`+"\n", x+5, y-8, languageColor(lang)))
+			body.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" class="muted">%s</text>`+"\n", x+15, y-4, escapeXML(lang)))
+			x += 15 + int(float64(textWidth(lang))*svgCharWidth) + 16
+		}
+		if !r.PushedAt.IsZero() {
+			body.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" class="muted">Updated %s</text>`+"\n", x, y-4, r.PushedAt.UTC().Format("2006-01-02")))
+		}
+		y += svgRepoGap
+	}
+	height := y + svgPadding
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-labelledby="title">`+"\n", width, height, width, height))
+	sb.WriteString(fmt.Sprintf("  <title id=\"title\">%s</title>\n", escapeXML(heading)))
+	sb.WriteString("  <style>\n")
+	sb.WriteString("    text { font-family: -apple-system, BlinkMacSystemFont, \"Segoe UI\", Helvetica, Arial, sans-serif; font-size: 12px; }\n")
+	sb.WriteString(fmt.Sprintf("    .title { font-size: 18px; font-weight: 600; fill: %s; }\n", theme.Title))
+	sb.WriteString(fmt.Sprintf("    .name { font-size: 14px; font-weight: 600; fill: %s; }\n", theme.Name))
+	sb.WriteString(fmt.Sprintf("    .text { fill: %s; }\n", theme.Text))
+	sb.WriteString(fmt.Sprintf("    .muted { fill: %s; }\n", theme.Muted))
+	sb.WriteString("  </style>\n")
+	sb.WriteString(fmt.Sprintf(`  <rect x="0.5" y="0.5" width="%d" height="%d" rx="6" fill="%s" stroke="%s"/>`+"\n", width-1, height-1, theme.Background, theme.Border))
+	sb.WriteString(fmt.Sprintf(`  <text x="%d" y="%d" class="title">%s</text>`+"\n", svgPadding, svgPadding+20, escapeXML(heading)))
+	sb.WriteString(body.String())
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+func languageColor(lang string) string {
+	if c, ok := languageColors[lang]; ok {
+		return c
+	}
+	return defaultLanguageColor
+}
+
+var xmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&apos;",
+)
+
+// escapeXML escapes text for SVG content and drops characters XML forbids.
+func escapeXML(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+			return -1
+		}
+		if r == 0xFFFE || r == 0xFFFF {
+			return -1
+		}
+		return r
+	}, s)
+	return xmlEscaper.Replace(s)
+}
+
+// wrapText splits s into lines of at most width columns, breaking at spaces
+// where possible. At most maxLines lines are returned; the last is cut with "…"
+// if text remains.
+func wrapText(s string, width, maxLines int) []string {
+	if s == "" || width <= 0 || maxLines <= 0 {
+		return nil
+	}
+
+	var lines []string
+	var line []rune
+	lineWidth := 0
+	lastSpace := -1
+	for _, r := range s {
+		w := runeWidth(r)
+		if lineWidth+w > width {
+			cut := len(line)
+			if r != ' ' && lastSpace > 0 {
+				cut = lastSpace
+			}
+			lines = append(lines, strings.TrimRight(string(line[:cut]), " "))
+			rest := []rune(strings.TrimLeft(string(line[cut:]), " "))
+			line = rest
+			lineWidth = textWidth(string(rest))
+			lastSpace = -1
+		}
+		if r == ' ' && len(line) == 0 {
+			continue
+		}
+		if r == ' ' {
+			lastSpace = len(line)
+		}
+		line = append(line, r)
+		lineWidth += w
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+
+	if len(lines) > maxLines {
+		last := []rune(lines[maxLines-1])
+		for len(last) > 0 && textWidth(string(last))+1 > width {
+			last = last[:len(last)-1]
+		}
+		lines = append(lines[:maxLines-1], strings.TrimRight(string(last), " ")+"…")
+	}
+	return lines
+}
+
+// textWidth returns the display width of s in columns.
+func textWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+// runeWidth treats East Asian ideographs and kana as two columns wide.
+func runeWidth(r rune) int {
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0xFF01 && r <= 0xFF60) {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+// assertWellFormedXML fails the test if s is not well-formed XML.
+func assertWellFormedXML(t *testing.T, s string) {
+	t.Helper()
+	dec := xml.NewDecoder(strings.NewReader(s))
+	for {
+		_, err := dec.Token()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("invalid XML: %v\n%s", err, s)
+		}
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	repos := []githubapi.Repository{
+		{
+			Name:            "awesome",
+			Description:     "An awesome project",
+			Language:        "Go",
+			StargazersCount: 42,
+			PushedAt:        time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	result := RenderSVG(repos, SVGOptions{})
+
+	assertWellFormedXML(t, result)
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="495"`,
+		`<title id="title">Current Projects</title>`,
+		`class="name">awesome</text>`,
+		`★ 42</text>`,
+		`class="text">An awesome project</text>`,
+		`fill="#00add8"`,
+		`>Go</text>`,
+		`>Updated 2025-01-15</text>`,
+		`fill="#ffffff"`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("output should contain %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestRenderSVGEscapes(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "a&b", Description: `<script>alert("x")</script>` + "\x01", Language: "C++"},
+	}
+
+	result := RenderSVG(repos, SVGOptions{Heading: "<Mine>"})
+
+	assertWellFormedXML(t, result)
+	if strings.Contains(result, "<script>") || strings.Contains(result, "<Mine>") {
+		t.Errorf("text should be escaped:\n%s", result)
+	}
+	if !strings.Contains(result, ">a&amp;b</text>") {
+		t.Errorf("expected escaped name:\n%s", result)
+	}
+}
+
+func TestRenderSVGThemeAndSize(t *testing.T) {
+	result := RenderSVG(nil, SVGOptions{Theme: SVGThemeDark, Width: 600})
+
+	assertWellFormedXML(t, result)
+	if !strings.Contains(result, `width="600"`) || !strings.Contains(result, `fill="#0d1117"`) {
+		t.Errorf("expected dark 600px card:\n%s", result)
+	}
+	if !strings.Contains(result, "No public projects matched.") {
+		t.Errorf("missing no-results message:\n%s", result)
+	}
+}
+
+func TestRenderSVGUnknownLanguageColor(t *testing.T) {
+	result := RenderSVG([]githubapi.Repository{{Name: "x", Language: "Brainfuck"}}, SVGOptions{})
+	if !strings.Contains(result, `fill="`+defaultLanguageColor+`"`) {
+		t.Errorf("expected default language color:\n%s", result)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		in       string
+		width    int
+		maxLines int
+		want     []string
+	}{
+		{"short", 10, 2, []string{"short"}},
+		{"hello world foo", 11, 2, []string{"hello world", "foo"}},
+		{"one two three four five", 9, 2, []string{"one two", "three…"}},
+		{"abcdefghij", 4, 3, []string{"abcd", "efgh", "ij"}},
+		{"日本語の説明", 6, 2, []string{"日本語", "の説明"}},
+		{"", 10, 2, nil},
+	}
+	for _, tt := range tests {
+		got := wrapText(tt.in, tt.width, tt.maxLines)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrapText(%q, %d, %d) = %q, want %q", tt.in, tt.width, tt.maxLines, got, tt.want)
+		}
+	}
+}
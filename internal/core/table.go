@@ -0,0 +1,187 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+// TableColumns lists the columns supported by RenderMarkdownTable, in default order.
+var TableColumns = []string{"name", "description", "language", "stars", "pushed"}
+
+// Table column alignments.
+const (
+	AlignNone   = ""
+	AlignLeft   = "left"
+	AlignCenter = "center"
+	AlignRight  = "right"
+)
+
+var tableColumnTitles = map[string]string{
+	"name":        "Name",
+	"description": "Description",
+	"language":    "Language",
+	"stars":       "Stars",
+	"pushed":      "Last Push",
+}
+
+// TableOptions controls Markdown table rendering.
+type TableOptions struct {
+	MarkdownOptions
+	// Columns selects and orders the columns. Empty means TableColumns.
+	Columns []string
+	// Align maps a column name to AlignLeft, AlignCenter or AlignRight.
+	Align map[string]string
+}
+
+// IsTableColumn reports whether name is a supported table column.
+func IsTableColumn(name string) bool {
+	_, ok := tableColumnTitles[name]
+	return ok
+}
+
+// ParseTableColumns parses a comma-separated list of table columns,
+// rejecting unknown and repeated names.
+func ParseTableColumns(v string) ([]string, error) {
+	var cols []string
+	seen := make(map[string]bool)
+	for _, c := range strings.Split(v, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if !IsTableColumn(c) {
+			return nil, fmt.Errorf("unknown column %q (valid: %s)", c, strings.Join(TableColumns, ", "))
+		}
+		if seen[c] {
+			return nil, fmt.Errorf("duplicate column %q", c)
+		}
+		seen[c] = true
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// ParseTableAlign parses comma-separated column:alignment pairs such as
+// "stars:right,name:center" into a TableOptions.Align map.
+func ParseTableAlign(v string) (map[string]string, error) {
+	align := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		col, a, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		col = strings.ToLower(strings.TrimSpace(col))
+		a = strings.ToLower(strings.TrimSpace(a))
+		if !ok || !IsTableColumn(col) {
+			return nil, fmt.Errorf("invalid alignment %q: want column:alignment with a valid column", pair)
+		}
+		switch a {
+		case AlignLeft, AlignCenter, AlignRight:
+		default:
+			return nil, fmt.Errorf("invalid alignment %q for column %q: want left, center or right", a, col)
+		}
+		align[col] = a
+	}
+	return align, nil
+}
+
+// RenderMarkdownTable produces the Markdown section for the given repos as a table.
+func RenderMarkdownTable(repos []githubapi.Repository, marker string, opts TableOptions) string {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = TableColumns
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<!-- BEGIN %s -->\n", marker))
+	writeHeading(&sb, opts.Heading)
+
+	pinned := min(opts.PinnedCount, len(repos))
+
+	switch {
+	case len(repos) == 0:
+		sb.WriteString("_No public projects matched._\n")
+	case pinned > 0:
+		sb.WriteString("### Pinned\n\n")
+		writeTable(&sb, repos[:pinned], columns, opts)
+		if pinned < len(repos) {
+			sb.WriteString("\n### More Projects\n\n")
+			writeTable(&sb, repos[pinned:], columns, opts)
+		}
+	default:
+		writeTable(&sb, repos, columns, opts)
+	}
+
+	sb.WriteString(fmt.Sprintf("<!-- END %s -->\n", marker))
+	return sb.String()
+}
+
+func writeTable(sb *strings.Builder, repos []githubapi.Repository, columns []string, opts TableOptions) {
+	titles := make([]string, len(columns))
+	seps := make([]string, len(columns))
+	for i, c := range columns {
+		titles[i] = tableColumnTitles[c]
+		seps[i] = alignmentRow(opts.Align[c])
+	}
+	writeTableRow(sb, titles)
+	writeTableRow(sb, seps)
+
+	for _, r := range repos {
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			cells[i] = tableCell(r, c, opts.MarkdownOptions)
+		}
+		writeTableRow(sb, cells)
+	}
+}
+
+func writeTableRow(sb *strings.Builder, cells []string) {
+	sb.WriteString("| ")
+	sb.WriteString(strings.Join(cells, " | "))
+	sb.WriteString(" |\n")
+}
+
+func alignmentRow(align string) string {
+	switch align {
+	case AlignLeft:
+		return ":---"
+	case AlignCenter:
+		return ":---:"
+	case AlignRight:
+		return "---:"
+	default:
+		return "---"
+	}
+}
+
+func tableCell(r githubapi.Repository, column string, opts MarkdownOptions) string {
+	switch column {
+	case "name":
+		name := escapeTableCell(strings.TrimSpace(r.Name))
+		link := repoLink(r, opts.PrivateStyle)
+		cell := name
+		if link != "" {
+			cell = fmt.Sprintf("[%s](%s)", name, link)
+		}
+		if r.Private {
+			cell += " (private)"
+		}
+		return cell
+	case "description":
+		return escapeTableCell(normalizeInlineText(r.Description))
+	case "language":
+		return escapeTableCell(strings.TrimSpace(r.Language))
+	case "stars":
+		return strconv.Itoa(r.StargazersCount)
+	case "pushed":
+		if r.PushedAt.IsZero() {
+			return ""
+		}
+		return r.PushedAt.UTC().Format("2006-01-02")
+	default:
+		return ""
+	}
+}
+
+// escapeTableCell escapes inline Markdown and the pipe character, which
+// would otherwise split the cell.
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(escapeMarkdownInline(s), "|", "\\|")
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+func TestRenderMarkdownTableDefaultColumns(t *testing.T) {
+	repos := []githubapi.Repository{
+		{
+			Name:            "awesome",
+			HTMLURL:         "https://github.com/u/awesome",
+			Description:     "An awesome project",
+			Language:        "Go",
+			StargazersCount: 42,
+			PushedAt:        time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	result := RenderMarkdownTable(repos, "CURRENT PROJECTS", TableOptions{})
+
+	expected := "<!-- BEGIN CURRENT PROJECTS -->\n" +
+		"## Current Projects\n\n" +
+		"| Name | Description | Language | Stars | Last Push |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| [awesome](https://github.com/u/awesome) | An awesome project | Go | 42 | 2025-01-15 |\n" +
+		"<!-- END CURRENT PROJECTS -->\n"
+	if result != expected {
+		t.Errorf("unexpected table:\n%s\nwant:\n%s", result, expected)
+	}
+}
+
+func TestRenderMarkdownTableColumnsAndAlignment(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "x", HTMLURL: "https://github.com/u/x", StargazersCount: 3},
+	}
+
+	result := RenderMarkdownTable(repos, "M", TableOptions{
+		Columns: []string{"stars", "name"},
+		Align:   map[string]string{"stars": AlignRight, "name": AlignCenter},
+	})
+
+	if !strings.Contains(result, "| Stars | Name |\n| ---: | :---: |\n| 3 | [x](https://github.com/u/x) |\n") {
+		t.Errorf("unexpected table: %s", result)
+	}
+}
+
+func TestRenderMarkdownTableEscapesPipes(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "a|b", Description: "left | right\nnext", Language: "C|C++"},
+	}
+
+	result := RenderMarkdownTable(repos, "M", TableOptions{Columns: []string{"name", "description", "language"}})
+
+	if !strings.Contains(result, `| a\|b | left \| right next | C\|C++ |`) {
+		t.Errorf("pipes should be escaped: %s", result)
+	}
+}
+
+func TestRenderMarkdownTablePrivateNoLink(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "secret", HTMLURL: "https://github.com/u/secret", Private: true},
+	}
+
+	result := RenderMarkdownTable(repos, "M", TableOptions{Columns: []string{"name"}})
+
+	if strings.Contains(result, "https://github.com/u/secret") {
+		t.Errorf("private link should be omitted: %s", result)
+	}
+	if !strings.Contains(result, "| secret (private) |") {
+		t.Errorf("expected private label: %s", result)
+	}
+}
+
+func TestRenderMarkdownTableEmpty(t *testing.T) {
+	result := RenderMarkdownTable(nil, "M", TableOptions{})
+	if !strings.Contains(result, "_No public projects matched._") {
+		t.Errorf("missing no-results message: %s", result)
+	}
+	if strings.Contains(result, "| Name |") {
+		t.Errorf("empty result should not render a table header: %s", result)
+	}
+}
+
+func TestRenderMarkdownTablePatchable(t *testing.T) {
+	existing := "# Profile\n\n<!-- BEGIN CURRENT PROJECTS -->\nold\n<!-- END CURRENT PROJECTS -->\n"
+	section := RenderMarkdownTable([]githubapi.Repository{{Name: "n"}}, "CURRENT PROJECTS", TableOptions{})
+
+	result, err := PatchREADME(existing, section, "CURRENT PROJECTS", false)
+	if err != nil {
+		t.Fatalf("PatchREADME: %v", err)
+	}
+	if strings.Contains(result.Content, "old") || !strings.Contains(result.Content, "| n |") {
+		t.Errorf("unexpected patched content: %s", result.Content)
+	}
+}
+
+func TestRenderMarkdownTablePinned(t *testing.T) {
+	repos := []githubapi.Repository{
+		{Name: "pinned", HTMLURL: "https://github.com/u/pinned"},
+		{Name: "other", HTMLURL: "https://github.com/u/other"},
+	}
+
+	result := RenderMarkdownTable(repos, "M", TableOptions{
+		MarkdownOptions: MarkdownOptions{PinnedCount: 1},
+		Columns:         []string{"name"},
+	})
+	want := "<!-- BEGIN M -->\n## Current Projects\n\n" +
+		"### Pinned\n\n| Name |\n| --- |\n| [pinned](https://github.com/u/pinned) |\n" +
+		"\n### More Projects\n\n| Name |\n| --- |\n| [other](https://github.com/u/other) |\n" +
+		"<!-- END M -->\n"
+	if result != want {
+		t.Errorf("unexpected output:\ngot:\n%s\nwant:\n%s", result, want)
+	}
+}
+
+func TestParseTableColumns(t *testing.T) {
+	cols, err := ParseTableColumns(" Name, stars ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(cols, ",") != "name,stars" {
+		t.Errorf("columns = %v, want [name stars]", cols)
+	}
+
+	for _, v := range []string{"name,owner", "name,name", ""} {
+		if _, err := ParseTableColumns(v); err == nil {
+			t.Errorf("ParseTableColumns(%q): expected error", v)
+		}
+	}
+}
+
+func TestParseTableAlign(t *testing.T) {
+	align, err := ParseTableAlign("stars:Right, name:center")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if align["stars"] != AlignRight || align["name"] != AlignCenter || len(align) != 2 {
+		t.Errorf("align = %v", align)
+	}
+
+	for _, v := range []string{"stars", "owner:left", "stars:middle"} {
+		if _, err := ParseTableAlign(v); err == nil {
+			t.Errorf("ParseTableAlign(%q): expected error", v)
+		}
+	}
+}
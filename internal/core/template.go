@@ -0,0 +1,157 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+// TemplateData is the data model passed to user-defined Markdown templates.
+type TemplateData struct {
+	// Repos are the filtered, sorted and truncated repositories.
+	Repos []githubapi.Repository
+	// Groups holds Repos split into groups when grouping is enabled.
+	Groups []RepoGroup
+	// GeneratedAt is the time the output was generated.
+	GeneratedAt time.Time
+	// User is the GitHub user the repositories were fetched for.
+	User string
+	// Heading is the section heading ("Current Projects" unless configured).
+	Heading string
+	// Marker is the section marker name.
+	Marker string
+	// Filters describes the settings that produced Repos.
+	Filters TemplateFilters
+	// PrivateStyle decides whether the link helper returns URLs of private
+	// repositories. Empty means PrivateStyleNoLink.
+	PrivateStyle string
+}
+
+// TemplateFilters summarizes the filter, sort and top-N settings of a section.
+// It is also reported as "filters" in the JSON envelope.
+type TemplateFilters struct {
+	Top                int      `json:"top"`
+	MinStars           int      `json:"min_stars"`
+	SinceDays          int      `json:"since_days"`
+	IncludeForks       bool     `json:"include_forks"`
+	IncludeArchived    bool     `json:"include_archived"`
+	IncludePrivate     bool     `json:"include_private"`
+	RequireDescription bool     `json:"require_description"`
+	Topics             []string `json:"topics"`
+	TagMatch           string   `json:"tag_match"`
+	Sort               string   `json:"sort"`
+}
+
+// templateFuncs returns the helper functions available to templates.
+// relativeTime is measured against now and link follows privateStyle.
+//
+//   - escape: escapes Markdown inline syntax, table pipes and HTML, and
+//     collapses newlines
+//   - sanitizeURL: returns the URL if it is http(s), otherwise ""
+//   - link: returns the sanitized URL of a repository, or "" for a private
+//     repository unless privateStyle is PrivateStyleLabel
+//   - relativeTime: formats a time relative to generation, e.g. "3 days ago"
+//   - truncate: shortens a string to n runes, appending "…" when cut
+func templateFuncs(now time.Time, privateStyle string) template.FuncMap {
+	return template.FuncMap{
+		"escape": func(s string) string {
+			return escapeTableCell(normalizeInlineText(s))
+		},
+		"sanitizeURL": sanitizeMarkdownURL,
+		"link": func(r githubapi.Repository) string {
+			return repoLink(r, privateStyle)
+		},
+		"relativeTime": func(t time.Time) string {
+			return relativeTime(t, now)
+		},
+		"truncate": truncateRunes,
+	}
+}
+
+// ParseMarkdownTemplate parses a user-defined template with the helper functions registered.
+func ParseMarkdownTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(templateFuncs(time.Time{}, "")).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// RenderMarkdownTemplate executes tmpl with data and wraps the result in the
+// BEGIN/END markers for data.Marker, so the output can be patched into a README.
+func RenderMarkdownTemplate(tmpl *template.Template, data TemplateData) (string, error) {
+	if data.GeneratedAt.IsZero() {
+		data.GeneratedAt = time.Now()
+	}
+	if strings.TrimSpace(data.Heading) == "" {
+		data.Heading = "Current Projects"
+	}
+
+	bound, err := tmpl.Clone()
+	if err != nil {
+		return "", fmt.Errorf("cloning template: %w", err)
+	}
+	bound.Funcs(templateFuncs(data.GeneratedAt, data.PrivateStyle))
+
+	var body strings.Builder
+	if err := bound.Execute(&body, data); err != nil {
+		return "", fmt.Errorf("executing template: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<!-- BEGIN %s -->\n", data.Marker))
+	sb.WriteString(body.String())
+	if body.Len() > 0 && !strings.HasSuffix(body.String(), "\n") {
+		sb.WriteByte('\n')
+	}
+	sb.WriteString(fmt.Sprintf("<!-- END %s -->\n", data.Marker))
+	return sb.String(), nil
+}
+
+func relativeTime(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := now.Sub(t)
+	if d < 0 {
+		return "just now"
+	}
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute") + " ago"
+	case d < 24*time.Hour:
+		return plural(int(d/time.Hour), "hour") + " ago"
+	case d < 30*24*time.Hour:
+		return plural(int(d/(24*time.Hour)), "day") + " ago"
+	case d < 365*24*time.Hour:
+		return plural(int(d/(30*24*time.Hour)), "month") + " ago"
+	default:
+		return plural(int(d/(365*24*time.Hour)), "year") + " ago"
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func truncateRunes(n int, s string) string {
+	if n <= 0 {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	if n == 1 {
+		return "…"
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+func TestRenderMarkdownTemplateTable(t *testing.T) {
+	tmpl, err := ParseMarkdownTemplate("table", `## {{ .Heading }} for {{ .User }}
+
+| Name | Stars | Pushed |
+|---|---|---|
+{{- range .Repos }}
+| **[{{ escape .Name }}]({{ sanitizeURL .HTMLURL }})** | {{ .StargazersCount }} | {{ relativeTime .PushedAt }} |
+{{- end }}
+`)
+	if err != nil {
+		t.Fatalf("ParseMarkdownTemplate: %v", err)
+	}
+
+	now := time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)
+	repos := []githubapi.Repository{
+		{Name: "awesome", HTMLURL: "https://github.com/u/awesome", StargazersCount: 42, PushedAt: now.Add(-72 * time.Hour)},
+	}
+
+	result, err := RenderMarkdownTemplate(tmpl, TemplateData{
+		Repos:       repos,
+		GeneratedAt: now,
+		User:        "u",
+		Marker:      "CURRENT PROJECTS",
+	})
+	if err != nil {
+		t.Fatalf("RenderMarkdownTemplate: %v", err)
+	}
+
+	if !strings.HasPrefix(result, "<!-- BEGIN CURRENT PROJECTS -->\n## Current Projects for u\n") {
+		t.Errorf("missing BEGIN marker or default heading: %s", result)
+	}
+	if !strings.HasSuffix(result, "\n<!-- END CURRENT PROJECTS -->\n") {
+		t.Errorf("missing END marker: %s", result)
+	}
+	if !strings.Contains(result, "| **[awesome](https://github.com/u/awesome)** | 42 | 3 days ago |") {
+		t.Errorf("unexpected table row: %s", result)
+	}
+}
+
+func TestRenderMarkdownTemplateHelpers(t *testing.T) {
+	tmpl, err := ParseMarkdownTemplate("helpers", `{{ range .Repos }}{{ escape .Description }}|{{ sanitizeURL .HTMLURL }}|{{ truncate 5 .Name }}{{ end }}`)
+	if err != nil {
+		t.Fatalf("ParseMarkdownTemplate: %v", err)
+	}
+
+	repos := []githubapi.Repository{
+		{Name: "very-long-name", HTMLURL: "javascript:alert(1)", Description: "<b>bold</b>\nnext"},
+	}
+	result, err := RenderMarkdownTemplate(tmpl, TemplateData{Repos: repos, Marker: "M"})
+	if err != nil {
+		t.Fatalf("RenderMarkdownTemplate: %v", err)
+	}
+
+	if !strings.Contains(result, "&lt;b&gt;bold&lt;/b&gt; next||very…\n") {
+		t.Errorf("unexpected helper output: %q", result)
+	}
+}
+
+func TestRenderMarkdownTemplateExecutionError(t *testing.T) {
+	tmpl, err := ParseMarkdownTemplate("bad", `{{ .NoSuchField }}`)
+	if err != nil {
+		t.Fatalf("ParseMarkdownTemplate: %v", err)
+	}
+	if _, err := RenderMarkdownTemplate(tmpl, TemplateData{Marker: "M"}); err == nil {
+		t.Fatal("expected execution error for unknown field")
+	}
+}
+
+func TestParseMarkdownTemplateSyntaxError(t *testing.T) {
+	if _, err := ParseMarkdownTemplate("bad", `{{ range .Repos }}`); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{5 * time.Hour, "5 hours ago"},
+		{24 * time.Hour, "1 day ago"},
+		{60 * 24 * time.Hour, "2 months ago"},
+		{800 * 24 * time.Hour, "2 years ago"},
+	}
+	for _, tt := range tests {
+		if got := relativeTime(now.Add(-tt.ago), now); got != tt.want {
+			t.Errorf("relativeTime(-%s) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+	if got := relativeTime(time.Time{}, now); got != "" {
+		t.Errorf("relativeTime(zero) = %q, want empty", got)
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	if got := truncateRunes(10, "short"); got != "short" {
+		t.Errorf("truncateRunes(10, short) = %q", got)
+	}
+	if got := truncateRunes(4, "日本語テキスト"); got != "日本語…" {
+		t.Errorf("truncateRunes(4, ...) = %q", got)
+	}
+	if got := truncateRunes(0, "x"); got != "" {
+		t.Errorf("truncateRunes(0, x) = %q", got)
+	}
+}
+
+func TestRenderMarkdownTemplateLinkHonorsPrivateStyle(t *testing.T) {
+	tmpl, err := ParseMarkdownTemplate("link", `{{ range .Repos }}{{ .Name }}={{ link . }};{{ end }}`)
+	if err != nil {
+		t.Fatalf("ParseMarkdownTemplate: %v", err)
+	}
+
+	repos := []githubapi.Repository{
+		{Name: "public", HTMLURL: "https://github.com/u/public"},
+		{Name: "secret", HTMLURL: "https://github.com/u/secret", Private: true},
+	}
+	for _, tc := range []struct {
+		style string
+		want  string
+	}{
+		{"", "public=https://github.com/u/public;secret=;"},
+		{PrivateStyleNoLink, "public=https://github.com/u/public;secret=;"},
+		{PrivateStyleLabel, "public=https://github.com/u/public;secret=https://github.com/u/secret;"},
+	} {
+		result, err := RenderMarkdownTemplate(tmpl, TemplateData{Repos: repos, Marker: "M", PrivateStyle: tc.style})
+		if err != nil {
+			t.Fatalf("RenderMarkdownTemplate: %v", err)
+		}
+		if !strings.Contains(result, tc.want+"\n") {
+			t.Errorf("style %q: got %q, want %q", tc.style, result, tc.want)
+		}
+	}
+}
+
+func TestRenderMarkdownTemplateEscapePipe(t *testing.T) {
+	tmpl, err := ParseMarkdownTemplate("pipe", `| {{ range .Repos }}{{ escape .Description }}{{ end }} |`)
+	if err != nil {
+		t.Fatalf("ParseMarkdownTemplate: %v", err)
+	}
+
+	repos := []githubapi.Repository{{Name: "r", Description: "a | b"}}
+	result, err := RenderMarkdownTemplate(tmpl, TemplateData{Repos: repos, Marker: "M"})
+	if err != nil {
+		t.Fatalf("RenderMarkdownTemplate: %v", err)
+	}
+	if !strings.Contains(result, `| a \| b |`) {
+		t.Errorf("pipe should be escaped: %q", result)
+	}
+}
@@ -0,0 +1,86 @@
+package githubapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CacheEntry is a cached page response used for conditional requests.
+type CacheEntry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Link         string `json:"link,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// DiskCache stores page responses as JSON files in a directory.
+type DiskCache struct {
+	Dir string
+}
+
+// NewDiskCache creates a cache rooted at dir.
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{Dir: dir}
+}
+
+// Get returns the entry for key, or false if there is none or it is unreadable.
+func (c *DiskCache) Get(key string) (*CacheEntry, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Put stores entry under key.
+func (c *DiskCache) Put(key string, entry *CacheEntry) error {
+	if err := os.MkdirAll(c.Dir, 0o700); err != nil {
+		return fmt.Errorf("creating cache dir: %w", err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling cache entry: %w", err)
+	}
+	tmp, err := os.CreateTemp(c.Dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating cache file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	return nil
+}
+
+func (c *DiskCache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
+
+// cacheKey derives a file-safe key from the request URL and token, so that
+// responses fetched with different credentials are never shared.
+func cacheKey(pageURL, token string) string {
+	h := sha256.New()
+	h.Write([]byte(pageURL))
+	h.Write([]byte{0})
+	tokenSum := sha256.Sum256([]byte(token))
+	h.Write(tokenSum[:])
+	return hex.EncodeToString(h.Sum(nil))
+}
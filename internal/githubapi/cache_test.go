@@ -0,0 +1,104 @@
+package githubapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFetchUsesCacheOnNotModified(t *testing.T) {
+	var full, notModified int32
+	mux := http.NewServeMux()
+	var serverURL string
+	mux.HandleFunc("/users/u/repos", func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		etag := `"etag-` + page + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&full, 1)
+		w.Header().Set("ETag", etag)
+		switch page {
+		case "1":
+			w.Header().Set("Link", `<`+serverURL+`/users/u/repos?type=owner&per_page=100&page=2>; rel="next"`)
+			if _, err := w.Write([]byte(`[{"name":"a"}]`)); err != nil {
+				t.Errorf("writing response: %v", err)
+			}
+		case "2":
+			if _, err := w.Write([]byte(`[{"name":"b"}]`)); err != nil {
+				t.Errorf("writing response: %v", err)
+			}
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	serverURL = server.URL
+
+	cache := NewDiskCache(t.TempDir())
+
+	for i := 0; i < 2; i++ {
+		client := NewClient(server.URL, "", 0, nil)
+		client.Cache = cache
+		repos, err := client.FetchAllRepos("u")
+		if err != nil {
+			t.Fatalf("run %d: FetchAllRepos: %v", i, err)
+		}
+		if len(repos) != 2 || repos[0].Name != "a" || repos[1].Name != "b" {
+			t.Fatalf("run %d: unexpected repos: %+v", i, repos)
+		}
+	}
+
+	if full != 2 {
+		t.Errorf("expected 2 full responses, got %d", full)
+	}
+	if notModified != 2 {
+		t.Errorf("expected 2 not-modified responses, got %d", notModified)
+	}
+}
+
+func TestFetchWithoutValidatorsIsNotCached(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			t.Error("unexpected conditional request")
+		}
+		if _, err := w.Write([]byte(`[]`)); err != nil {
+			t.Errorf("writing response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", 0, nil)
+	client.Cache = NewDiskCache(t.TempDir())
+	for i := 0; i < 2; i++ {
+		if _, err := client.FetchAllRepos("u"); err != nil {
+			t.Fatalf("FetchAllRepos: %v", err)
+		}
+	}
+}
+
+func TestCacheKeyDependsOnToken(t *testing.T) {
+	if cacheKey("https://x/repos", "a") == cacheKey("https://x/repos", "b") {
+		t.Error("cache keys for different tokens should differ")
+	}
+	if cacheKey("https://x/repos", "a") != cacheKey("https://x/repos", "a") {
+		t.Error("cache key should be deterministic")
+	}
+}
+
+func TestDiskCacheRoundTrip(t *testing.T) {
+	cache := NewDiskCache(t.TempDir() + "/nested")
+	if _, ok := cache.Get("missing"); ok {
+		t.Fatal("expected miss")
+	}
+	entry := &CacheEntry{URL: "u", ETag: `"x"`, Body: []byte(`[]`)}
+	if err := cache.Put("k", entry); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, ok := cache.Get("k")
+	if !ok || got.ETag != `"x"` || string(got.Body) != "[]" {
+		t.Errorf("unexpected entry: %+v", got)
+	}
+}
@@ -1,11 +1,14 @@
 package githubapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -15,6 +18,13 @@ import (
 // RepoFetcher is the interface for fetching repositories.
 type RepoFetcher interface {
 	FetchAllRepos(user string) ([]Repository, error)
+	FetchAllReposContext(ctx context.Context, user string) ([]Repository, error)
+}
+
+// OrgRepoFetcher is a RepoFetcher that can also list organization repositories.
+type OrgRepoFetcher interface {
+	RepoFetcher
+	FetchOrgReposContext(ctx context.Context, org string) ([]Repository, error)
 }
 
 // Client communicates with the GitHub REST API.
@@ -23,6 +33,12 @@ type Client struct {
 	Token      string
 	HTTPClient *http.Client
 	Logger     *log.Logger
+	Retry      RetryPolicy
+	// Cache enables conditional requests when non-nil.
+	Cache *DiskCache
+
+	// sleep is replaceable in tests; nil means a context-aware timer wait.
+	sleep func(time.Duration)
 }
 
 // NewClient creates a new GitHub API client.
@@ -37,28 +53,144 @@ func NewClient(baseURL, token string, timeout time.Duration, logger *log.Logger)
 			Timeout: timeout,
 		},
 		Logger: logger,
+		Retry:  DefaultRetryPolicy(),
 	}
 }
 
 // FetchAllRepos fetches all public repositories for a user, handling pagination.
 func (c *Client) FetchAllRepos(user string) ([]Repository, error) {
+	return c.FetchAllReposContext(context.Background(), user)
+}
+
+// FetchAllReposContext is like FetchAllRepos but aborts when ctx is done.
+func (c *Client) FetchAllReposContext(ctx context.Context, user string) ([]Repository, error) {
+	return c.fetchAllPages(ctx, fmt.Sprintf("%s/users/%s/repos?type=owner&per_page=100&page=1", c.BaseURL, user))
+}
+
+// FetchOrgRepos fetches all repositories visible for an organization, handling pagination.
+func (c *Client) FetchOrgRepos(org string) ([]Repository, error) {
+	return c.FetchOrgReposContext(context.Background(), org)
+}
+
+// FetchOrgReposContext is like FetchOrgRepos but aborts when ctx is done.
+func (c *Client) FetchOrgReposContext(ctx context.Context, org string) ([]Repository, error) {
+	return c.fetchAllPages(ctx, fmt.Sprintf("%s/orgs/%s/repos?type=all&per_page=100&page=1", c.BaseURL, org))
+}
+
+// FetchAuthenticatedRepos fetches repositories of the authenticated user via
+// /user/repos, including private ones when visibility allows. It requires a token.
+// affiliation is a comma-separated list (owner, collaborator, organization_member);
+// visibility is one of all, public or private. Empty values use the API defaults.
+func (c *Client) FetchAuthenticatedRepos(affiliation, visibility string) ([]Repository, error) {
+	return c.FetchAuthenticatedReposContext(context.Background(), affiliation, visibility)
+}
+
+// FetchAuthenticatedReposContext is like FetchAuthenticatedRepos but aborts when ctx is done.
+func (c *Client) FetchAuthenticatedReposContext(ctx context.Context, affiliation, visibility string) ([]Repository, error) {
+	if c.Token == "" {
+		return nil, fmt.Errorf("fetching authenticated repos requires a token")
+	}
+	q := url.Values{}
+	if affiliation != "" {
+		q.Set("affiliation", affiliation)
+	}
+	if visibility != "" {
+		q.Set("visibility", visibility)
+	}
+	q.Set("per_page", "100")
+	q.Set("page", "1")
+	return c.fetchAllPages(ctx, fmt.Sprintf("%s/user/repos?%s", c.BaseURL, q.Encode()))
+}
+
+// fetchAllPages follows the Link "next" relation starting at url and
+// returns the concatenated results of every page.
+func (c *Client) fetchAllPages(ctx context.Context, pageURL string) ([]Repository, error) {
 	var allRepos []Repository
-	url := fmt.Sprintf("%s/users/%s/repos?type=owner&per_page=100&page=1", c.BaseURL, user)
 
-	for url != "" {
-		repos, nextURL, err := c.fetchPage(url)
+	for pageURL != "" {
+		repos, nextURL, err := c.fetchPage(ctx, pageURL)
 		if err != nil {
 			return nil, err
 		}
 		allRepos = append(allRepos, repos...)
-		url = nextURL
+		pageURL = nextURL
 	}
 
 	return allRepos, nil
 }
 
-func (c *Client) fetchPage(url string) ([]Repository, string, error) {
-	req, err := http.NewRequest("GET", url, nil)
+// fetchPage fetches a single page, retrying transient failures according to c.Retry.
+func (c *Client) fetchPage(ctx context.Context, pageURL string) ([]Repository, string, error) {
+	var repos []Repository
+	var nextURL string
+	err := c.withRetry(ctx, pageURL, func() error {
+		var err error
+		repos, nextURL, err = c.fetchPageOnce(ctx, pageURL)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return repos, nextURL, nil
+}
+
+// withRetry calls fn until it succeeds, returns a non-retryable error, or
+// c.Retry is exhausted. target names the resource in error messages.
+func (c *Client) withRetry(ctx context.Context, target string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("fetching repos from %s: %w", target, ctxErr)
+		}
+
+		var re *retryableError
+		if !errors.As(err, &re) || attempt >= c.Retry.MaxAttempts {
+			return err
+		}
+
+		wait := re.wait
+		if wait == 0 {
+			// Unlike a server-requested wait, the minimum yields to MaxWait.
+			minWait := re.minWait
+			if c.Retry.MaxWait > 0 {
+				minWait = min(minWait, c.Retry.MaxWait)
+			}
+			wait = max(c.Retry.backoff(attempt), minWait)
+		}
+		if c.Retry.MaxWait > 0 && wait > c.Retry.MaxWait {
+			return fmt.Errorf("%w (retry would wait %s, exceeding max wait %s)",
+				err, wait.Round(time.Second), c.Retry.MaxWait)
+		}
+
+		c.Logger.Printf("Request failed (attempt %d/%d): %v; retrying in %s",
+			attempt, c.Retry.MaxAttempts, err, wait.Round(time.Millisecond))
+		if err := c.doSleep(ctx, wait); err != nil {
+			return fmt.Errorf("waiting to retry %s: %w", target, err)
+		}
+	}
+}
+
+// doSleep waits for d or until ctx is done, whichever comes first.
+func (c *Client) doSleep(ctx context.Context, d time.Duration) error {
+	if c.sleep != nil {
+		c.sleep(d)
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) fetchPageOnce(ctx context.Context, pageURL string) ([]Repository, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
 	if err != nil {
 		return nil, "", fmt.Errorf("creating request: %w", err)
 	}
@@ -68,28 +200,81 @@ func (c *Client) fetchPage(url string) ([]Repository, string, error) {
 		req.Header.Set("Authorization", "Bearer "+c.Token)
 	}
 
+	var key string
+	var cached *CacheEntry
+	if c.Cache != nil {
+		key = cacheKey(pageURL, c.Token)
+		if entry, ok := c.Cache.Get(key); ok {
+			cached = entry
+			if entry.ETag != "" {
+				req.Header.Set("If-None-Match", entry.ETag)
+			}
+			if entry.LastModified != "" {
+				req.Header.Set("If-Modified-Since", entry.LastModified)
+			}
+		}
+	}
+
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, "", fmt.Errorf("fetching repos from %s: %w", url, err)
+		return nil, "", &retryableError{err: fmt.Errorf("fetching repos from %s: %w", pageURL, err)}
 	}
 	defer resp.Body.Close()
 
 	c.logRateLimit(resp)
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		c.Logger.Printf("Not modified, using cached response: %s", pageURL)
+		return decodePage(cached.Body, cached.Link)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, "", fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(body))
+		err := newStatusError(resp, string(body))
+		if re := retryableStatus(resp, string(body), err); re != nil {
+			return nil, "", re
+		}
+		return nil, "", err
 	}
 
-	var repos []Repository
-	if err := json.NewDecoder(resp.Body).Decode(&repos); err != nil {
-		return nil, "", fmt.Errorf("decoding response: %w", err)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", &retryableError{err: fmt.Errorf("reading response from %s: %w", pageURL, err)}
+	}
+
+	repos, nextURL, err := decodePage(body, resp.Header.Get("Link"))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if c.Cache != nil {
+		etag := resp.Header.Get("ETag")
+		lastModified := resp.Header.Get("Last-Modified")
+		if etag != "" || lastModified != "" {
+			entry := &CacheEntry{
+				URL:          pageURL,
+				ETag:         etag,
+				LastModified: lastModified,
+				Link:         resp.Header.Get("Link"),
+				Body:         body,
+			}
+			if err := c.Cache.Put(key, entry); err != nil {
+				c.Logger.Printf("Warning: %v", err)
+			}
+		}
 	}
 
-	nextURL := ParseNextLink(resp.Header.Get("Link"))
 	return repos, nextURL, nil
 }
 
+func decodePage(body []byte, linkHeader string) ([]Repository, string, error) {
+	var repos []Repository
+	if err := json.Unmarshal(body, &repos); err != nil {
+		return nil, "", fmt.Errorf("decoding response: %w", err)
+	}
+	return repos, ParseNextLink(linkHeader), nil
+}
+
 func (c *Client) logRateLimit(resp *http.Response) {
 	rl := ParseRateLimit(resp.Header)
 	if rl.Limit > 0 {
@@ -128,3 +313,16 @@ func ParseRateLimit(h http.Header) RateLimit {
 	}
 	return rl
 }
+
+// ProfileURL derives the web profile URL of a user or organization from a REST
+// base URL: https://api.github.com maps to https://github.com, and GitHub
+// Enterprise Server's /api/v3 suffix is removed.
+func ProfileURL(baseURL, login string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+	baseURL = strings.TrimSuffix(baseURL, "/api/v3")
+	if u, err := url.Parse(baseURL); err == nil && strings.EqualFold(u.Host, "api.github.com") {
+		u.Host = "github.com"
+		baseURL = u.String()
+	}
+	return baseURL + "/" + url.PathEscape(login)
+}
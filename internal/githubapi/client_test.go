@@ -77,3 +77,17 @@ func TestParseRateLimitEmpty(t *testing.T) {
 		t.Errorf("Expected zero values for empty headers, got %+v", rl)
 	}
 }
+
+func TestProfileURL(t *testing.T) {
+	tests := map[string]string{
+		"https://api.github.com":          "https://github.com/octocat",
+		"https://api.github.com/":         "https://github.com/octocat",
+		"https://ghe.example.com/api/v3":  "https://ghe.example.com/octocat",
+		"https://ghe.example.com/api/v3/": "https://ghe.example.com/octocat",
+	}
+	for in, want := range tests {
+		if got := ProfileURL(in, "octocat"); got != want {
+			t.Errorf("ProfileURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package githubapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// NotFoundError is returned when the requested user or organization does not exist (404).
+type NotFoundError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *NotFoundError) Error() string {
+	return statusMessage(e.StatusCode, e.Body)
+}
+
+// AuthError is returned when the token is missing, invalid or lacks
+// permission for the request (401, or 403 that is not a rate limit).
+type AuthError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *AuthError) Error() string {
+	return statusMessage(e.StatusCode, e.Body)
+}
+
+// RateLimitError is returned when a primary or secondary rate limit was hit
+// (429, or 403 with rate-limit headers). Reset is the time the limit is
+// expected to lift; it is zero when unknown.
+type RateLimitError struct {
+	StatusCode int
+	Body       string
+	Reset      time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	msg := statusMessage(e.StatusCode, e.Body)
+	if !e.Reset.IsZero() {
+		msg += fmt.Sprintf(" (rate limit resets at %s)", e.Reset.Format(time.RFC3339))
+	}
+	return msg
+}
+
+// ServerError is returned for 5xx responses.
+type ServerError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *ServerError) Error() string {
+	return statusMessage(e.StatusCode, e.Body)
+}
+
+func statusMessage(status int, body string) string {
+	return fmt.Sprintf("GitHub API returned status %d: %s", status, body)
+}
+
+// newStatusError converts a non-200 response into a typed error.
+func newStatusError(resp *http.Response, body string) error {
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return &NotFoundError{StatusCode: resp.StatusCode, Body: body}
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusForbidden && isRateLimited(resp.Header, body):
+		return &RateLimitError{StatusCode: resp.StatusCode, Body: body, Reset: rateLimitReset(resp.Header)}
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return &AuthError{StatusCode: resp.StatusCode, Body: body}
+	case resp.StatusCode >= 500:
+		return &ServerError{StatusCode: resp.StatusCode, Body: body}
+	default:
+		return fmt.Errorf("%s", statusMessage(resp.StatusCode, body))
+	}
+}
+
+func isRateLimited(h http.Header, body string) bool {
+	if h.Get("Retry-After") != "" || h.Get("X-RateLimit-Remaining") == "0" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(body), "rate limit")
+}
+
+// rateLimitReset returns when the limit lifts, preferring Retry-After over X-RateLimit-Reset.
+func rateLimitReset(h http.Header) time.Time {
+	if wait := parseRetryAfter(h.Get("Retry-After")); wait > 0 {
+		return time.Now().Add(wait).Truncate(time.Second)
+	}
+	return ParseRateLimit(h).Reset
+}
@@ -0,0 +1,105 @@
+package githubapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewStatusErrorTypes(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		body   string
+		check  func(error) bool
+	}{
+		{
+			name:   "not found",
+			status: http.StatusNotFound,
+			check:  func(err error) bool { var e *NotFoundError; return errors.As(err, &e) },
+		},
+		{
+			name:   "unauthorized",
+			status: http.StatusUnauthorized,
+			check:  func(err error) bool { var e *AuthError; return errors.As(err, &e) },
+		},
+		{
+			name:   "forbidden without rate limit",
+			status: http.StatusForbidden,
+			body:   `{"message":"Resource not accessible by integration"}`,
+			check:  func(err error) bool { var e *AuthError; return errors.As(err, &e) },
+		},
+		{
+			name:   "forbidden primary rate limit",
+			status: http.StatusForbidden,
+			header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000000"},
+			check: func(err error) bool {
+				var e *RateLimitError
+				return errors.As(err, &e) && e.Reset.Equal(time.Unix(1700000000, 0))
+			},
+		},
+		{
+			name:   "forbidden secondary rate limit",
+			status: http.StatusForbidden,
+			body:   `{"message":"You have exceeded a secondary rate limit"}`,
+			check:  func(err error) bool { var e *RateLimitError; return errors.As(err, &e) },
+		},
+		{
+			name:   "too many requests",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{"Retry-After": "60"},
+			check: func(err error) bool {
+				var e *RateLimitError
+				return errors.As(err, &e) && e.Reset.After(time.Now())
+			},
+		},
+		{
+			name:   "bad gateway",
+			status: http.StatusBadGateway,
+			check:  func(err error) bool { var e *ServerError; return errors.As(err, &e) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.header {
+				resp.Header.Set(k, v)
+			}
+			err := newStatusError(resp, tt.body)
+			if !tt.check(err) {
+				t.Errorf("unexpected error type %T: %v", err, err)
+			}
+		})
+	}
+}
+
+func TestFetchAllReposTypedErrorAfterRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", 0, nil)
+	client.Retry = RetryPolicy{MaxAttempts: 2}
+	_, err := client.FetchAllRepos("u")
+
+	var se *ServerError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected *ServerError, got %T: %v", err, err)
+	}
+	if se.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("StatusCode = %d, want 503", se.StatusCode)
+	}
+}
+
+func TestRateLimitErrorMessageIncludesReset(t *testing.T) {
+	err := &RateLimitError{StatusCode: 403, Body: "limited", Reset: time.Unix(1700000000, 0).UTC()}
+	if !strings.Contains(err.Error(), "2023-11-14T22:13:20Z") {
+		t.Errorf("message should include reset time: %v", err)
+	}
+}
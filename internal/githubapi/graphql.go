@@ -0,0 +1,369 @@
+package githubapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GraphQLClient fetches repositories from the GitHub GraphQL v4 API.
+// It reuses the HTTP client, token, logger and retry policy of a REST Client.
+type GraphQLClient struct {
+	client *Client
+
+	// Endpoint is the GraphQL URL, derived from the REST base URL by default.
+	Endpoint string
+	// ExtraFields are additional selections added to each repository node,
+	// e.g. "releases { totalCount }". Results are stored in Repository.Extra,
+	// keyed by the field name or alias.
+	ExtraFields []string
+}
+
+// NewGraphQLClient creates a GraphQL fetcher that shares settings with client.
+func NewGraphQLClient(client *Client, extraFields []string) *GraphQLClient {
+	return &GraphQLClient{
+		client:      client,
+		Endpoint:    GraphQLEndpoint(client.BaseURL),
+		ExtraFields: extraFields,
+	}
+}
+
+// GraphQLEndpoint derives the GraphQL endpoint from a REST base URL.
+// GitHub Enterprise Server serves REST at /api/v3 and GraphQL at /api/graphql.
+func GraphQLEndpoint(baseURL string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if strings.HasSuffix(baseURL, "/api/v3") {
+		return strings.TrimSuffix(baseURL, "/v3") + "/graphql"
+	}
+	return baseURL + "/graphql"
+}
+
+// graphQLRepoFields is the selection for each repository node.
+const graphQLRepoFields = `
+        name
+        nameWithOwner
+        url
+        description
+        isFork
+        isArchived
+        isPrivate
+        primaryLanguage { name }
+        stargazerCount
+        pushedAt
+        repositoryTopics(first: 20) { nodes { topic { name } } }`
+
+// FetchAllRepos fetches all public repositories owned by a user.
+func (g *GraphQLClient) FetchAllRepos(user string) ([]Repository, error) {
+	return g.FetchAllReposContext(context.Background(), user)
+}
+
+// FetchAllReposContext is like FetchAllRepos but aborts when ctx is done.
+func (g *GraphQLClient) FetchAllReposContext(ctx context.Context, user string) ([]Repository, error) {
+	return g.fetchOwnerRepos(ctx, user, "ownerAffiliations: [OWNER], privacy: PUBLIC")
+}
+
+// FetchOrgRepos fetches all repositories of an organization visible to the token.
+func (g *GraphQLClient) FetchOrgRepos(org string) ([]Repository, error) {
+	return g.FetchOrgReposContext(context.Background(), org)
+}
+
+// FetchOrgReposContext is like FetchOrgRepos but aborts when ctx is done.
+func (g *GraphQLClient) FetchOrgReposContext(ctx context.Context, org string) ([]Repository, error) {
+	return g.fetchOwnerRepos(ctx, org, "ownerAffiliations: [OWNER]")
+}
+
+// FetchPinnedRepos fetches the repositories pinned on a user's or organization's profile,
+// in pinned order.
+func (g *GraphQLClient) FetchPinnedRepos(login string) ([]Repository, error) {
+	return g.FetchPinnedReposContext(context.Background(), login)
+}
+
+// FetchPinnedReposContext is like FetchPinnedRepos but aborts when ctx is done.
+func (g *GraphQLClient) FetchPinnedReposContext(ctx context.Context, login string) ([]Repository, error) {
+	fields := g.repoFields()
+	query := `query($login: String!) {
+  repositoryOwner(login: $login) {
+    ... on ProfileOwner {
+      pinnedItems(first: 6, types: [REPOSITORY]) {
+        nodes {
+          ... on Repository {` + fields + `
+          }
+        }
+      }
+    }
+  }
+}`
+
+	var resp graphQLPinnedResponse
+	if err := g.client.withRetry(ctx, g.Endpoint, func() error {
+		resp = graphQLPinnedResponse{}
+		return g.post(ctx, graphQLRequest{Query: query, Variables: map[string]any{"login": login}}, &resp)
+	}); err != nil {
+		return nil, err
+	}
+	if err := graphQLErrorsToError(resp.Errors); err != nil {
+		return nil, err
+	}
+	owner := resp.Data.RepositoryOwner
+	if owner == nil {
+		return nil, &NotFoundError{StatusCode: http.StatusNotFound, Body: fmt.Sprintf("no user or organization named %q", login)}
+	}
+
+	var repos []Repository
+	for _, raw := range owner.PinnedItems.Nodes {
+		repo, err := g.mapNode(raw)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// buildQuery assembles the repositories query for the given connection arguments.
+func (g *GraphQLClient) buildQuery(args string) string {
+	fields := g.repoFields()
+	return `query($login: String!, $cursor: String) {
+  repositoryOwner(login: $login) {
+    repositories(first: 100, after: $cursor, ` + args + `, orderBy: {field: PUSHED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {` + fields + `
+      }
+    }
+  }
+}`
+}
+
+// repoFields returns the repository node selection including ExtraFields.
+func (g *GraphQLClient) repoFields() string {
+	fields := graphQLRepoFields
+	for _, f := range g.ExtraFields {
+		fields += "\n        " + f
+	}
+	return fields
+}
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type graphQLReposResponse struct {
+	Data struct {
+		RepositoryOwner *struct {
+			Repositories struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []json.RawMessage `json:"nodes"`
+			} `json:"repositories"`
+		} `json:"repositoryOwner"`
+	} `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+type graphQLPinnedResponse struct {
+	Data struct {
+		RepositoryOwner *struct {
+			PinnedItems struct {
+				Nodes []json.RawMessage `json:"nodes"`
+			} `json:"pinnedItems"`
+		} `json:"repositoryOwner"`
+	} `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+type graphQLRepoNode struct {
+	Name            string `json:"name"`
+	NameWithOwner   string `json:"nameWithOwner"`
+	URL             string `json:"url"`
+	Description     string `json:"description"`
+	IsFork          bool   `json:"isFork"`
+	IsArchived      bool   `json:"isArchived"`
+	IsPrivate       bool   `json:"isPrivate"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	StargazerCount   int       `json:"stargazerCount"`
+	PushedAt         time.Time `json:"pushedAt"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+}
+
+func (g *GraphQLClient) fetchOwnerRepos(ctx context.Context, login, args string) ([]Repository, error) {
+	query := g.buildQuery(args)
+	var allRepos []Repository
+	var cursor *string
+
+	for {
+		vars := map[string]any{"login": login, "cursor": cursor}
+		var resp graphQLReposResponse
+		if err := g.client.withRetry(ctx, g.Endpoint, func() error {
+			resp = graphQLReposResponse{}
+			return g.post(ctx, graphQLRequest{Query: query, Variables: vars}, &resp)
+		}); err != nil {
+			return nil, err
+		}
+
+		if err := graphQLErrorsToError(resp.Errors); err != nil {
+			return nil, err
+		}
+		owner := resp.Data.RepositoryOwner
+		if owner == nil {
+			return nil, &NotFoundError{StatusCode: http.StatusNotFound, Body: fmt.Sprintf("no user or organization named %q", login)}
+		}
+
+		for _, raw := range owner.Repositories.Nodes {
+			repo, err := g.mapNode(raw)
+			if err != nil {
+				return nil, err
+			}
+			allRepos = append(allRepos, repo)
+		}
+
+		pageInfo := owner.Repositories.PageInfo
+		if !pageInfo.HasNextPage || pageInfo.EndCursor == "" {
+			break
+		}
+		next := pageInfo.EndCursor
+		cursor = &next
+	}
+
+	return allRepos, nil
+}
+
+// post sends a single GraphQL request and decodes the response body into out.
+func (g *GraphQLClient) post(ctx context.Context, body graphQLRequest, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding GraphQL request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if g.client.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.client.Token)
+	}
+
+	resp, err := g.client.HTTPClient.Do(req)
+	if err != nil {
+		return &retryableError{err: fmt.Errorf("querying %s: %w", g.Endpoint, err)}
+	}
+	defer resp.Body.Close()
+
+	g.client.logRateLimit(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		err := newStatusError(resp, string(data))
+		if re := retryableStatus(resp, string(data), err); re != nil {
+			return re
+		}
+		return err
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding GraphQL response: %w", err)
+	}
+	return nil
+}
+
+// graphQLErrorsToError converts GraphQL-level errors into the package's typed errors.
+func graphQLErrorsToError(errs []graphQLError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Message
+	}
+	body := strings.Join(msgs, "; ")
+	switch errs[0].Type {
+	case "NOT_FOUND":
+		return &NotFoundError{StatusCode: http.StatusNotFound, Body: body}
+	case "RATE_LIMITED":
+		return &RateLimitError{StatusCode: http.StatusForbidden, Body: body}
+	case "FORBIDDEN":
+		return &AuthError{StatusCode: http.StatusForbidden, Body: body}
+	default:
+		return errors.New("GitHub GraphQL API returned errors: " + body)
+	}
+}
+
+// mapNode converts a repository node into a Repository, collecting ExtraFields.
+func (g *GraphQLClient) mapNode(raw json.RawMessage) (Repository, error) {
+	var node graphQLRepoNode
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return Repository{}, fmt.Errorf("decoding repository node: %w", err)
+	}
+
+	repo := Repository{
+		Name:            node.Name,
+		FullName:        node.NameWithOwner,
+		HTMLURL:         node.URL,
+		Description:     node.Description,
+		Fork:            node.IsFork,
+		Archived:        node.IsArchived,
+		Private:         node.IsPrivate,
+		StargazersCount: node.StargazerCount,
+		PushedAt:        node.PushedAt,
+	}
+	if node.PrimaryLanguage != nil {
+		repo.Language = node.PrimaryLanguage.Name
+	}
+	for _, t := range node.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, t.Topic.Name)
+	}
+
+	if len(g.ExtraFields) > 0 {
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &all); err != nil {
+			return Repository{}, fmt.Errorf("decoding repository node: %w", err)
+		}
+		repo.Extra = make(map[string]json.RawMessage, len(g.ExtraFields))
+		for _, f := range g.ExtraFields {
+			key := GraphQLFieldKey(f)
+			if v, ok := all[key]; ok {
+				repo.Extra[key] = v
+			}
+		}
+	}
+
+	return repo, nil
+}
+
+// GraphQLFieldKey returns the response key of a field selection: the alias
+// if one is given ("commits: history { totalCount }"), otherwise the field name.
+func GraphQLFieldKey(selection string) string {
+	s := strings.TrimSpace(selection)
+	end := strings.IndexAny(s, " \t\n({")
+	head := s
+	if end >= 0 {
+		head = s[:end]
+	}
+	if i := strings.Index(head, ":"); i >= 0 {
+		return head[:i]
+	}
+	return head
+}
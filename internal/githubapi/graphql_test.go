@@ -0,0 +1,182 @@
+package githubapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newGraphQLTestServer serves two pages of repositories for login "testuser"
+// and a NOT_FOUND error for any other login.
+func newGraphQLTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if !strings.Contains(req.Query, "repositoryOwner(login: $login)") {
+			t.Errorf("unexpected query: %s", req.Query)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.Variables["login"] != "testuser" {
+			if _, err := w.Write([]byte(`{"data":{"repositoryOwner":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a RepositoryOwner"}]}`)); err != nil {
+				t.Errorf("writing response: %v", err)
+			}
+			return
+		}
+
+		var body string
+		switch req.Variables["cursor"] {
+		case nil:
+			body = `{"data":{"repositoryOwner":{"repositories":{
+				"pageInfo":{"hasNextPage":true,"endCursor":"c1"},
+				"nodes":[{"name":"alpha","nameWithOwner":"testuser/alpha","url":"https://github.com/testuser/alpha",
+					"description":"First","isFork":false,"isArchived":false,"isPrivate":false,
+					"primaryLanguage":{"name":"Go"},"stargazerCount":7,"pushedAt":"2025-01-15T10:00:00Z",
+					"repositoryTopics":{"nodes":[{"topic":{"name":"cli"}}]},
+					"releases":{"totalCount":3}}]}}}}`
+		case "c1":
+			body = `{"data":{"repositoryOwner":{"repositories":{
+				"pageInfo":{"hasNextPage":false,"endCursor":"c2"},
+				"nodes":[{"name":"beta","nameWithOwner":"testuser/beta","url":"https://github.com/testuser/beta",
+					"description":null,"isFork":true,"isArchived":false,"isPrivate":false,
+					"primaryLanguage":null,"stargazerCount":0,"pushedAt":"2024-06-01T00:00:00Z",
+					"repositoryTopics":{"nodes":[]},
+					"releases":{"totalCount":0}}]}}}}`
+		default:
+			t.Errorf("unexpected cursor: %v", req.Variables["cursor"])
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Errorf("writing response: %v", err)
+		}
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestGraphQLFetchAllReposPaginated(t *testing.T) {
+	server := newGraphQLTestServer(t)
+	defer server.Close()
+
+	g := NewGraphQLClient(NewClient(server.URL, "tok", 0, nil), []string{"releases { totalCount }"})
+	repos, err := g.FetchAllRepos("testuser")
+	if err != nil {
+		t.Fatalf("FetchAllRepos: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("expected 2 repos, got %d", len(repos))
+	}
+
+	a := repos[0]
+	if a.Name != "alpha" || a.FullName != "testuser/alpha" || a.HTMLURL != "https://github.com/testuser/alpha" {
+		t.Errorf("unexpected identity fields: %+v", a)
+	}
+	if a.Language != "Go" || a.StargazersCount != 7 || a.Description != "First" {
+		t.Errorf("unexpected detail fields: %+v", a)
+	}
+	if len(a.Topics) != 1 || a.Topics[0] != "cli" {
+		t.Errorf("Topics = %v, want [cli]", a.Topics)
+	}
+	if string(a.Extra["releases"]) != `{"totalCount":3}` {
+		t.Errorf("Extra[releases] = %s", a.Extra["releases"])
+	}
+
+	b := repos[1]
+	if !b.Fork || b.Language != "" || b.Description != "" {
+		t.Errorf("unexpected second repo: %+v", b)
+	}
+}
+
+func TestGraphQLNotFound(t *testing.T) {
+	server := newGraphQLTestServer(t)
+	defer server.Close()
+
+	g := NewGraphQLClient(NewClient(server.URL, "tok", 0, nil), nil)
+	_, err := g.FetchAllRepos("nobody")
+	var nf *NotFoundError
+	if !errors.As(err, &nf) {
+		t.Fatalf("expected *NotFoundError, got %T: %v", err, err)
+	}
+}
+
+func TestGraphQLQueryIncludesExtraFields(t *testing.T) {
+	g := NewGraphQLClient(NewClient("https://api.github.com", "tok", 0, nil), []string{"commits: defaultBranchRef { name }"})
+	q := g.buildQuery("ownerAffiliations: [OWNER]")
+	if !strings.Contains(q, "commits: defaultBranchRef { name }") {
+		t.Errorf("extra field missing from query: %s", q)
+	}
+}
+
+func TestGraphQLEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"https://api.github.com":          "https://api.github.com/graphql",
+		"https://api.github.com/":         "https://api.github.com/graphql",
+		"https://ghe.example.com/api/v3":  "https://ghe.example.com/api/graphql",
+		"https://ghe.example.com/api/v3/": "https://ghe.example.com/api/graphql",
+	}
+	for in, want := range tests {
+		if got := GraphQLEndpoint(in); got != want {
+			t.Errorf("GraphQLEndpoint(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGraphQLFieldKey(t *testing.T) {
+	tests := map[string]string{
+		"releases { totalCount }":            "releases",
+		"commits: defaultBranchRef { name }": "commits",
+		"watchers(first: 1) { totalCount }":  "watchers",
+		"  forkCount  ":                      "forkCount",
+		"latestRelease{ tagName }":           "latestRelease",
+	}
+	for in, want := range tests {
+		if got := GraphQLFieldKey(in); got != want {
+			t.Errorf("GraphQLFieldKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGraphQLFetchPinnedRepos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if !strings.Contains(req.Query, "pinnedItems(first: 6, types: [REPOSITORY])") {
+			t.Errorf("unexpected query: %s", req.Query)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		body := `{"data":{"repositoryOwner":{"pinnedItems":{"nodes":[
+			{"name":"second","nameWithOwner":"testuser/second","url":"https://github.com/testuser/second","pushedAt":"2024-01-01T00:00:00Z","repositoryTopics":{"nodes":[]}},
+			{"name":"first","nameWithOwner":"testuser/first","url":"https://github.com/testuser/first","pushedAt":"2025-01-01T00:00:00Z","repositoryTopics":{"nodes":[]}}
+		]}}}}`
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Errorf("writing response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	g := NewGraphQLClient(NewClient(server.URL, "tok", 0, nil), nil)
+	repos, err := g.FetchPinnedRepos("testuser")
+	if err != nil {
+		t.Fatalf("FetchPinnedRepos: %v", err)
+	}
+	if len(repos) != 2 || repos[0].FullName != "testuser/second" || repos[1].FullName != "testuser/first" {
+		t.Errorf("pinned order not preserved: %+v", repos)
+	}
+}
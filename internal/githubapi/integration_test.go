@@ -144,3 +144,80 @@ func TestFetchAllReposAPIError(t *testing.T) {
 		t.Errorf("error should mention status code: %v", err)
 	}
 }
+
+func TestFetchOrgReposPaginated(t *testing.T) {
+	mux := http.NewServeMux()
+	var serverURL string
+
+	mux.HandleFunc("/orgs/acme/repos", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "", "1":
+			w.Header().Set("Link", `<`+serverURL+`/orgs/acme/repos?type=all&per_page=100&page=2>; rel="next"`)
+			if _, err := w.Write([]byte(`[{"name":"tool","full_name":"acme/tool"}]`)); err != nil {
+				t.Errorf("writing page1 response: %v", err)
+			}
+		case "2":
+			if _, err := w.Write([]byte(`[{"name":"lib","full_name":"acme/lib"}]`)); err != nil {
+				t.Errorf("writing page2 response: %v", err)
+			}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	serverURL = server.URL
+
+	client := NewClient(server.URL, "", 0, nil)
+	repos, err := client.FetchOrgRepos("acme")
+	if err != nil {
+		t.Fatalf("FetchOrgRepos: %v", err)
+	}
+	if len(repos) != 2 || repos[0].FullName != "acme/tool" || repos[1].FullName != "acme/lib" {
+		t.Errorf("unexpected repos: %+v", repos)
+	}
+}
+
+func TestFetchAuthenticatedRepos(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user/repos", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q", got)
+		}
+		q := r.URL.Query()
+		if q.Get("affiliation") != "owner,collaborator" {
+			t.Errorf("affiliation = %q", q.Get("affiliation"))
+		}
+		if q.Get("visibility") != "private" {
+			t.Errorf("visibility = %q", q.Get("visibility"))
+		}
+		if q.Get("type") != "" {
+			t.Errorf("type must not be combined with affiliation, got %q", q.Get("type"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte(`[{"name":"secret","full_name":"me/secret","private":true}]`)); err != nil {
+			t.Errorf("writing response: %v", err)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(server.URL, "tok", 0, nil)
+	repos, err := client.FetchAuthenticatedRepos("owner,collaborator", "private")
+	if err != nil {
+		t.Fatalf("FetchAuthenticatedRepos: %v", err)
+	}
+	if len(repos) != 1 || !repos[0].Private {
+		t.Errorf("unexpected repos: %+v", repos)
+	}
+}
+
+func TestFetchAuthenticatedReposRequiresToken(t *testing.T) {
+	client := NewClient("http://127.0.0.1:0", "", 0, nil)
+	if _, err := client.FetchAuthenticatedRepos("owner", "all"); err == nil {
+		t.Fatal("expected error without token")
+	}
+}
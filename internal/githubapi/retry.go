@@ -0,0 +1,139 @@
+package githubapi
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per request, including the
+	// first one. Values <= 1 disable retries.
+	MaxAttempts int
+	// BaseDelay is the initial backoff delay, doubled after each attempt.
+	BaseDelay time.Duration
+	// MaxWait is the longest single wait the client accepts. When the server
+	// asks for a longer wait (Retry-After or X-RateLimit-Reset), the request
+	// fails immediately instead of sleeping. It also caps the minimum wait
+	// after a secondary rate limit.
+	MaxWait time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewClient.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxWait:     time.Minute,
+	}
+}
+
+// secondaryRateLimitWait is the minimum wait after a rate-limit response
+// that says neither how long to wait nor when the limit resets, as with
+// GitHub's secondary rate limits. GitHub asks clients to wait at least a
+// minute before retrying.
+const secondaryRateLimitWait = time.Minute
+
+// retryableError marks an error that may succeed when the request is retried.
+// wait is the server-requested delay, or zero to use exponential backoff.
+// minWait is a lower bound on that backoff, itself capped by MaxWait.
+type retryableError struct {
+	err     error
+	wait    time.Duration
+	minWait time.Duration
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// backoff returns the jittered exponential delay before the given retry
+// (1 for the first retry). The result lies in [d/2, d) where d = base*2^(retry-1).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		return 0
+	}
+	d := base << (retry - 1)
+	if d <= 0 || (p.MaxWait > 0 && d > p.MaxWait) {
+		d = p.MaxWait
+	}
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// retryableStatus wraps err, the error for resp, in a retryableError when the
+// response status is worth retrying, with the server-requested wait if any.
+// It returns nil for permanent failures.
+func retryableStatus(resp *http.Response, body string, err error) *retryableError {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return rateLimitError(resp.Header, err)
+	case http.StatusForbidden:
+		// Only rate-limit 403s are transient; others are permission problems.
+		if isRateLimited(resp.Header, body) {
+			return rateLimitError(resp.Header, err)
+		}
+		return nil
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return &retryableError{err: err, wait: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	default:
+		return nil
+	}
+}
+
+// rateLimitError returns the retryableError for a rate-limited response.
+// Without a wait or reset hint it backs off for at least
+// secondaryRateLimitWait, since retrying sooner fails again.
+func rateLimitError(h http.Header, err error) *retryableError {
+	if wait := rateLimitWait(h); wait > 0 {
+		return &retryableError{err: err, wait: wait}
+	}
+	return &retryableError{err: err, minWait: secondaryRateLimitWait}
+}
+
+// rateLimitWait returns how long to wait according to Retry-After or,
+// when the primary limit is exhausted, X-RateLimit-Reset.
+func rateLimitWait(h http.Header) time.Duration {
+	if wait := parseRetryAfter(h.Get("Retry-After")); wait > 0 {
+		return wait
+	}
+	if h.Get("X-RateLimit-Remaining") == "0" {
+		rl := ParseRateLimit(h)
+		if !rl.Reset.IsZero() {
+			if wait := time.Until(rl.Reset); wait > 0 {
+				return wait + time.Second
+			}
+		}
+	}
+	return 0
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if wait := time.Until(t); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
@@ -0,0 +1,201 @@
+package githubapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newRetryTestClient(url string, policy RetryPolicy) (*Client, *[]time.Duration) {
+	var waits []time.Duration
+	client := NewClient(url, "", 0, nil)
+	client.Retry = policy
+	client.sleep = func(d time.Duration) { waits = append(waits, d) }
+	return client, &waits
+}
+
+func TestFetchRetriesServerError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if _, err := w.Write([]byte(`[{"name":"ok"}]`)); err != nil {
+			t.Errorf("writing response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client, waits := newRetryTestClient(server.URL, RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxWait: time.Second})
+	repos, err := client.FetchAllRepos("u")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repos) != 1 {
+		t.Fatalf("expected 1 repo, got %d", len(repos))
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+	if len(*waits) != 1 || (*waits)[0] < 5*time.Millisecond || (*waits)[0] >= 10*time.Millisecond {
+		t.Errorf("unexpected backoff waits: %v", *waits)
+	}
+}
+
+func TestFetchHonorsRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if _, err := w.Write([]byte(`[]`)); err != nil {
+			t.Errorf("writing response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client, waits := newRetryTestClient(server.URL, RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxWait: time.Minute})
+	if _, err := client.FetchAllRepos("u"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Errorf("waits = %v, want [7s]", *waits)
+	}
+}
+
+func TestFetchSecondaryRateLimitWaitsAMinute(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		maxWait time.Duration
+		want    time.Duration
+	}{
+		{"default", 5 * time.Minute, time.Minute},
+		{"capped by max wait", 10 * time.Second, 10 * time.Second},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					w.WriteHeader(http.StatusForbidden)
+					if _, err := w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`)); err != nil {
+						t.Errorf("writing response: %v", err)
+					}
+					return
+				}
+				if _, err := w.Write([]byte(`[]`)); err != nil {
+					t.Errorf("writing response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			client, waits := newRetryTestClient(server.URL, RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxWait: tc.maxWait})
+			if _, err := client.FetchAllRepos("u"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*waits) != 1 || (*waits)[0] != tc.want {
+				t.Errorf("waits = %v, want [%s]", *waits, tc.want)
+			}
+		})
+	}
+}
+
+func TestFetchRateLimitResetExceedsMaxWait(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client, waits := newRetryTestClient(server.URL, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxWait: time.Second})
+	_, err := client.FetchAllRepos("u")
+	if err == nil {
+		t.Fatal("expected error when reset is beyond max wait")
+	}
+	if !strings.Contains(err.Error(), "exceeding max wait") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if calls != 1 || len(*waits) != 0 {
+		t.Errorf("expected a single call without sleeping, got calls=%d waits=%v", calls, *waits)
+	}
+}
+
+func TestFetchDoesNotRetryPlainForbidden(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client, _ := newRetryTestClient(server.URL, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxWait: time.Second})
+	if _, err := client.FetchAllRepos("u"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestFetchGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, waits := newRetryTestClient(server.URL, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxWait: time.Second})
+	_, err := client.FetchAllRepos("u")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "503") {
+		t.Errorf("error should mention status code: %v", err)
+	}
+	if calls != 3 || len(*waits) != 2 {
+		t.Errorf("expected 3 calls and 2 waits, got calls=%d waits=%v", calls, *waits)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxWait: 300 * time.Millisecond}
+	for i := 0; i < 20; i++ {
+		if d := p.backoff(1); d < 50*time.Millisecond || d >= 100*time.Millisecond {
+			t.Fatalf("backoff(1) = %v, want [50ms, 100ms)", d)
+		}
+		if d := p.backoff(2); d < 100*time.Millisecond || d >= 200*time.Millisecond {
+			t.Fatalf("backoff(2) = %v, want [100ms, 200ms)", d)
+		}
+		// Capped by MaxWait
+		if d := p.backoff(5); d < 150*time.Millisecond || d >= 300*time.Millisecond {
+			t.Fatalf("backoff(5) = %v, want [150ms, 300ms)", d)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("30"); got != 30*time.Second {
+		t.Errorf("parseRetryAfter(30) = %v", got)
+	}
+	if got := parseRetryAfter(""); got != 0 {
+		t.Errorf("parseRetryAfter(\"\") = %v", got)
+	}
+	if got := parseRetryAfter("garbage"); got != 0 {
+		t.Errorf("parseRetryAfter(garbage) = %v", got)
+	}
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(future); got <= 0 || got > time.Minute {
+		t.Errorf("parseRetryAfter(date) = %v", got)
+	}
+}
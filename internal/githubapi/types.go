@@ -1,6 +1,9 @@
 package githubapi
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Repository represents the minimal GitHub repository fields we need.
 type Repository struct {
@@ -15,6 +18,10 @@ type Repository struct {
 	Language        string    `json:"language"`
 	StargazersCount int       `json:"stargazers_count"`
 	PushedAt        time.Time `json:"pushed_at"`
+
+	// Extra holds additional GraphQL fields requested via GraphQLClient.ExtraFields,
+	// keyed by field name or alias. It is empty for REST results.
+	Extra map[string]json.RawMessage `json:"extra,omitempty"`
 }
 
 // RateLimit holds rate-limit information from response headers.
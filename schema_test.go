@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinshin86/github-current-projects/internal/core"
+	"github.com/shinshin86/github-current-projects/internal/githubapi"
+)
+
+// TestJSONEnvelopeMatchesSchema renders the JSON envelope from test data and
+// validates it against schema/output.schema.json.
+func TestJSONEnvelopeMatchesSchema(t *testing.T) {
+	schema := loadSchema(t)
+
+	data, err := os.ReadFile("testdata/repos_page1.json")
+	if err != nil {
+		t.Fatalf("reading testdata: %v", err)
+	}
+	var repos []githubapi.Repository
+	if err := json.Unmarshal(data, &repos); err != nil {
+		t.Fatalf("decoding testdata: %v", err)
+	}
+	repos = append(repos, githubapi.Repository{
+		Name:  "no-push",
+		Extra: map[string]json.RawMessage{"releases": json.RawMessage(`{"totalCount":3}`)},
+	})
+
+	filtered := core.FilterRepos(repos, core.FilterOptions{IncludeForks: true, IncludeArchived: true})
+	core.SortRepos(filtered)
+
+	tests := map[string]core.JSONEnvelopeOptions{
+		"flat": {
+			GeneratedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			User:        "testuser",
+			Filters:     core.TemplateFilters{Top: 10, TagMatch: "any", Sort: "pushed"},
+		},
+		"grouped": {
+			User:    "testuser",
+			Filters: core.TemplateFilters{Topics: []string{"go"}, TagMatch: "all", Sort: "stars"},
+			Groups:  core.GroupRepos(filtered, core.GroupOptions{By: "language"}),
+		},
+	}
+	for name, opts := range tests {
+		output, err := core.RenderJSONEnvelope(filtered, opts)
+		if err != nil {
+			t.Fatalf("%s: RenderJSONEnvelope: %v", name, err)
+		}
+		var doc any
+		if err := json.Unmarshal([]byte(output), &doc); err != nil {
+			t.Fatalf("%s: invalid JSON: %v", name, err)
+		}
+		if err := validateSchema(schema, schema, doc, "$"); err != nil {
+			t.Errorf("%s: output does not match schema: %v\n%s", name, err, output)
+		}
+	}
+}
+
+// TestSchemaValidatorRejectsInvalid guards against a validator that accepts everything.
+func TestSchemaValidatorRejectsInvalid(t *testing.T) {
+	schema := loadSchema(t)
+
+	invalid := []string{
+		`[]`,
+		`{"generated_at": "", "user": "", "filters": {}, "repos": []}`,
+		`{"schema_version": 2, "generated_at": "", "user": "", "filters": {"top": 0, "min_stars": 0, "since_days": 0, "include_forks": false, "include_archived": false, "include_private": false, "require_description": false, "topics": [], "tag_match": "any", "sort": "pushed"}, "repos": []}`,
+		`{"schema_version": 1, "generated_at": "", "user": "", "filters": {"top": 0, "min_stars": 0, "since_days": 0, "include_forks": false, "include_archived": false, "include_private": false, "require_description": false, "topics": [], "tag_match": "any", "sort": "pushed"}, "repos": [{"name": 1}]}`,
+		`{"schema_version": 1, "generated_at": "", "user": "", "filters": {"top": 0, "min_stars": 0, "since_days": 0, "include_forks": false, "include_archived": false, "include_private": false, "require_description": false, "topics": [], "tag_match": "some", "sort": "pushed"}, "repos": []}`,
+	}
+	for _, s := range invalid {
+		var doc any
+		if err := json.Unmarshal([]byte(s), &doc); err != nil {
+			t.Fatalf("invalid test JSON %s: %v", s, err)
+		}
+		if err := validateSchema(schema, schema, doc, "$"); err == nil {
+			t.Errorf("expected validation error for %s", s)
+		}
+	}
+}
+
+func loadSchema(t *testing.T) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile("schema/output.schema.json")
+	if err != nil {
+		t.Fatalf("reading schema: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("decoding schema: %v", err)
+	}
+	return schema
+}
+
+// validateSchema checks v against the subset of JSON Schema used by
+// schema/output.schema.json: $ref (local), type, const, enum, minimum,
+// required, properties, additionalProperties and items.
+func validateSchema(root, schema map[string]any, v any, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := resolveRef(root, ref)
+		if err != nil {
+			return err
+		}
+		return validateSchema(root, target, v, path)
+	}
+
+	if typ, ok := schema["type"]; ok {
+		var types []string
+		switch tt := typ.(type) {
+		case string:
+			types = []string{tt}
+		case []any:
+			for _, x := range tt {
+				types = append(types, x.(string))
+			}
+		}
+		matched := false
+		for _, name := range types {
+			if jsonTypeMatches(name, v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: expected type %v, got %T", path, types, v)
+		}
+	}
+
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, v) {
+		return fmt.Errorf("%s: expected %v, got %v", path, c, v)
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if reflect.DeepEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, v, enum)
+		}
+	}
+
+	if min, ok := schema["minimum"].(float64); ok {
+		if n, isNum := v.(float64); isNum && n < min {
+			return fmt.Errorf("%s: %v is less than minimum %v", path, n, min)
+		}
+	}
+
+	if obj, ok := v.(map[string]any); ok {
+		if required, ok := schema["required"].([]any); ok {
+			for _, r := range required {
+				if _, present := obj[r.(string)]; !present {
+					return fmt.Errorf("%s: missing required property %q", path, r)
+				}
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sub, known := props[k].(map[string]any)
+			if !known {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					return fmt.Errorf("%s: unexpected property %q", path, k)
+				}
+				continue
+			}
+			if err := validateSchema(root, sub, obj[k], path+"."+k); err != nil {
+				return err
+			}
+		}
+	}
+
+	if arr, ok := v.([]any); ok {
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range arr {
+				if err := validateSchema(root, items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func resolveRef(root map[string]any, ref string) (map[string]any, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	var node any = root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		node = m[part]
+	}
+	target, ok := node.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unresolvable $ref %q", ref)
+	}
+	return target, nil
+}
+
+func jsonTypeMatches(name string, v any) bool {
+	switch name {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		n, ok := v.(float64)
+		return ok && n == math.Trunc(n)
+	case "null":
+		return v == nil
+	default:
+		return false
+	}
+}